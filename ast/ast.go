@@ -1,122 +1,814 @@
 package ast
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/alfredoprograma/gox/lexer"
 )
 
+// Kind of the function whose body is being parsed. Some statements and expressions
+// are only valid within certain kinds of functions.
+type functionKind int
+
+const (
+	noFunction functionKind = iota
+	plainFunction
+	method
+	initializer
+)
+
+// Kind of the class whose body is being parsed. This and super expressions are only
+// valid within certain kinds of classes.
+type classKind int
+
+const (
+	noClass classKind = iota
+	plainClass
+	subclass
+)
+
 type AST struct {
-	tokens  []lexer.Token
-	errors  []error
-	start   uint
-	current uint
+	tokens   []lexer.Token
+	errors   []error
+	warnings []error
+	start    uint
+	current  uint
+	function functionKind // kind of the innermost function body being parsed
+	class    classKind    // kind of the innermost class body being parsed
+	loops    []string     // labels of the loop bodies being parsed within the innermost function, empty for unlabeled loops
+	armArrow int          // index of the arrow after the match arm guard being parsed, zero when there is none
 }
 
 func New(tokens []lexer.Token) AST {
 	return AST{
-		tokens:  tokens,
-		errors:  make([]error, 0),
-		start:   0,
-		current: 0,
+		tokens:   tokens,
+		errors:   make([]error, 0),
+		warnings: make([]error, 0),
+		start:    0,
+		current:  0,
+		function: noFunction,
+		class:    noClass,
+	}
+}
+
+// Parses the whole tokens stream as a program, which is a sequence of statements.
+// Syntax errors don't stop the parsing process; the offending statement is replaced by
+// a placeholder and parsing continues from the next statement boundary.
+func (ast *AST) program() Program {
+	statements := make([]Stmt, 0)
+
+	for !ast.isEnd() {
+		statements = append(statements, ast.statementOrSynchronize())
+	}
+
+	return NewProgram(statements, ast.warnings)
+}
+
+// Parses a statement, recovering from any syntax error raised while doing it.
+//
+// When a syntax error is recovered, it is registered and tokens are discarded until
+// a statement boundary is found. In such case, returned statement is a BadStmt covering
+// the discarded tokens. Statements which can't consume any token are discarded as well,
+// so parsing always moves forward.
+func (ast *AST) statementOrSynchronize() (stmt Stmt) {
+	start := ast.current
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(syntaxError)
+
+			if !ok {
+				panic(r)
+			}
+
+			ast.registerError(err)
+			stmt = ast.discardFrom(start)
+		}
+	}()
+
+	stmt = ast.declaration()
+
+	if ast.current == start {
+		return ast.discardFrom(start)
 	}
+
+	return stmt
 }
 
-func (ast *AST) expr() Expr {
-	return ast.equality()
+// Discards tokens from the given start of a statement up to the next statement boundary,
+// and builds the placeholder which covers them. At least one token is discarded.
+func (ast *AST) discardFrom(start uint) Stmt {
+	if ast.current == start {
+		ast.advance()
+	}
+
+	depth := 0
+
+	for _, token := range ast.tokens[start:ast.current] {
+		switch token.Kind {
+		case lexer.LeftBrace:
+			depth++
+		case lexer.RightBrace:
+			depth = max(depth-1, 0)
+		}
+	}
+
+	ast.synchronize(depth)
+
+	return NewBadStmt(ast.spanFrom(ast.tokens[start]))
+}
+
+// Declaration is the top level construction of a program. It declares a new name
+// at current scope, or falls back into a regular statement.
+func (ast *AST) declaration() Stmt {
+	if ast.match(lexer.Var) {
+		return ast.varDeclaration()
+	}
+
+	if ast.match(lexer.Class) {
+		return ast.classDeclaration()
+	}
+
+	// Function keyword followed by a name declares a function; otherwise it starts an anonymous function expression.
+	if ast.check(lexer.Function) && ast.checkNext(lexer.Identifier) {
+		ast.advance()
+		return ast.functionDeclaration()
+	}
+
+	return ast.statement()
 }
 
-// Equality expression is built from left and right operands, and also by an == or != operator.
-// It parses the operands as comparison expressions.
-// If there is not any operator, just parses a comparison expression.
-func (ast *AST) equality() Expr {
-	left := ast.comparison()
+// Class declaration is built from its name, an optional superclass and a list of methods enclosed by braces.
+// Methods are declared like functions, but without the function keyword.
+// The method called init is the class initializer.
+//
+// class Name < Superclass { init(a) { ... } method() { ... } }
+func (ast *AST) classDeclaration() Stmt {
+	keyword := ast.previous()
+	name := ast.mustConsume(lexer.Identifier)
+	kind := plainClass
+	var superclass *Variable
+
+	if ast.match(lexer.Less) {
+		superclassName := ast.mustConsume(lexer.Identifier)
+		superclass = &Variable{superclassName.Lexeme, superclassName.Span()}
+		kind = subclass
+
+		if superclassName.Lexeme == name.Lexeme {
+			ast.registerError(newSyntaxError(fmt.Sprintf("class %s can't inherit from itself", name.Lexeme), superclassName.Span().Start))
+		}
+	}
+
+	brace := ast.mustConsume(lexer.LeftBrace)
+
+	enclosing := ast.class
+	ast.class = kind
+	defer func() { ast.class = enclosing }()
 
-	if ast.match(lexer.DoubleEqual, lexer.BangEqual) {
-		operator := ast.previous()
-		right := ast.comparison()
+	methods := make([]FunctionStmt, 0)
 
-		return NewBinary(left, operator.Kind, right)
+	for ast.check(lexer.Identifier) {
+		methodName := ast.advance()
+		kind := method
+
+		if methodName.Lexeme == INITIALIZER_NAME {
+			kind = initializer
+		}
+
+		params := ast.parameters()
+		ast.mustConsume(lexer.LeftBrace)
+		body := ast.functionBody(kind)
+
+		methods = append(methods, FunctionStmt{methodName.Lexeme, params, body, ast.spanFrom(methodName)})
 	}
 
-	return left
+	ast.mustClose(brace, lexer.RightBrace, lexer.Identifier)
+
+	return NewClassStmt(name.Lexeme, superclass, methods, ast.spanFrom(keyword))
+}
+
+// Function declaration is built from its name, a parenthesized list of parameters and its body.
+//
+// function name(a, b) { ... }
+func (ast *AST) functionDeclaration() Stmt {
+	keyword := ast.previous()
+	name := ast.mustConsume(lexer.Identifier)
+	params, body := ast.functionRest()
+
+	return NewFunctionStmt(name.Lexeme, params, body, ast.spanFrom(keyword))
 }
 
-// Comparison expression is built from left and right operands, and also by an >, >=, < or <= operator.
-// It parses the operands as term expressions.
-// If there is not any operator, just parses a term expression.
-func (ast *AST) comparison() Expr {
-	left := ast.term()
+// Parses the parameters list and the body of a function, which are shared by every function syntax.
+func (ast *AST) functionRest() ([]Pattern, []Stmt) {
+	params := ast.parameters()
+	ast.mustConsume(lexer.LeftBrace)
+
+	return params, ast.functionBody(plainFunction)
+}
 
-	if ast.match(lexer.Greater, lexer.GreaterEqual, lexer.Less, lexer.LessEqual) {
-		operator := ast.previous()
-		right := ast.term()
+// Parses a parenthesized and comma separated list of parameter patterns.
+func (ast *AST) parameters() []Pattern {
+	paren := ast.mustConsume(lexer.LeftParen)
+	return ast.parameterList(paren)
+}
 
-		return NewBinary(left, operator.Kind, right)
+// Parses a comma separated list of parameter patterns, optionally ended by a rest parameter.
+// Parameters with default value can only be followed by other ones with default value.
+// It assumes given opening paren is already consumed.
+func (ast *AST) parameterList(paren lexer.Token) []Pattern {
+	params := make([]Pattern, 0)
+	optional := false
+
+	if !ast.check(lexer.RightParen) {
+		for {
+			if ast.match(lexer.Ellipsis) {
+				ellipsis := ast.previous()
+				ast.mustConsume(lexer.Identifier)
+				params = append(params, NewRestPattern(ast.previous().Lexeme, ast.spanFrom(ellipsis)))
+				ast.mustClose(paren, lexer.RightParen)
+
+				return params
+			}
+
+			param := ast.patternWithDefault(ast.pattern())
+
+			if _, ok := param.(DefaultPattern); ok {
+				optional = true
+			} else if optional {
+				ast.registerError(newSyntaxError("parameter without default value can't follow parameters with default value", param.Span().Start))
+			}
+
+			params = append(params, param)
+
+			if !ast.match(lexer.Comma) {
+				break
+			}
+		}
 	}
 
-	return left
+	ast.mustClose(paren, lexer.RightParen, lexer.Comma)
+
+	return params
 }
 
-// Term expression is built from left and right operands, and also by an + or - operator.
-// It parses the operands as factor expressions.
-// If there is not any operator, just parses a factor expression.
-func (ast *AST) term() Expr {
-	left := ast.factor()
+// Parses the statements of a function body of the given kind. It assumes opening brace is already consumed.
+func (ast *AST) functionBody(kind functionKind) []Stmt {
+	enclosing, loops := ast.function, ast.loops
+	ast.function, ast.loops = kind, nil
+	defer func() { ast.function, ast.loops = enclosing, loops }()
 
-	if ast.match(lexer.Plus, lexer.Minus) {
-		operator := ast.previous()
-		right := ast.factor()
+	return ast.block()
+}
 
-		return NewBinary(left, operator.Kind, right)
+// Variable declaration is built from a target pattern and an optional initializer expression.
+// Initializer is required when target destructures a list or a map.
+//
+// var name = expr;
+//
+// var [first, ...rest] = expr;
+func (ast *AST) varDeclaration() Stmt {
+	keyword := ast.previous()
+	target := ast.pattern()
+	var initializer Expr
+
+	if _, isIdentifier := target.(IdentifierPattern); isIdentifier {
+		if ast.match(lexer.Equal) {
+			initializer = ast.expr()
+		}
+	} else {
+		ast.mustConsume(lexer.Equal)
+		initializer = ast.expr()
 	}
 
-	return left
+	ast.mustConsume(lexer.Semicolon)
+
+	return NewVarStmt(target, initializer, ast.spanFrom(keyword))
 }
 
-// Factor expression is built from left and right operands, and also by an * or / operator.
-// It parses the operands as unary expressions.
-// If there is not any operator, just parses a unary expression.
-func (ast *AST) factor() Expr {
-	left := ast.unary()
+// Pattern is either an identifier, a list pattern or a map pattern.
+func (ast *AST) pattern() Pattern {
+	if ast.match(lexer.LeftBracket) {
+		return ast.listPattern(ast.pattern)
+	}
 
-	if ast.match(lexer.Star, lexer.Slash) {
-		operator := ast.previous()
-		right := ast.unary()
+	if ast.match(lexer.LeftBrace) {
+		return ast.mapPattern(ast.pattern)
+	}
 
-		return NewBinary(left, operator.Kind, right)
+	if !ast.check(lexer.Identifier) {
+		panic(ast.unexpected(lexer.Identifier, lexer.LeftBracket, lexer.LeftBrace))
 	}
 
-	return left
+	name := ast.advance()
+
+	return NewIdentifierPattern(name.Lexeme, name.Span())
 }
 
-// Unary expression is built from operator and its right operand.
-// If there is not any operator, just parses a primary expression.
-func (ast *AST) unary() Expr {
-	if ast.match(lexer.Minus, lexer.Bang) {
-		operator := ast.previous()
-		expr := ast.mustPrimary()
+// Match pattern is a list of alternatives separated by pipes. Besides destructuring patterns,
+// each alternative can be a wildcard, a literal or an instance pattern.
+//
+// 1 | 2, [x, "y"], {kind: "user"}, User {name}, _
+func (ast *AST) matchPattern() Pattern {
+	alternatives := []Pattern{ast.matchAlternative()}
 
-		return NewUnary(operator.Kind, expr)
+	for ast.match(lexer.Pipe) {
+		alternatives = append(alternatives, ast.matchAlternative())
 	}
 
-	return ast.mustPrimary()
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+
+	return NewAlternativePattern(alternatives)
 }
 
-// Primary is the most simpler expression possible. It just holds a value.
-// Also, it can be a group expression, which basically holds another nested expression.
-func (ast *AST) mustPrimary() Expr {
-	if ast.match(lexer.True, lexer.False, lexer.Null, lexer.Number, lexer.String) {
+// Parses a single alternative of a match pattern.
+func (ast *AST) matchAlternative() Pattern {
+	switch {
+	case ast.match(lexer.Underscore):
+		return NewWildcardPattern(ast.previous().Span())
+	case ast.match(lexer.True, lexer.False, lexer.Null, lexer.Number, lexer.String):
 		token := ast.previous()
-		return NewLiteral(token.Lexeme, token.Kind)
+
+		if !ast.checkNumber(token.Lexeme, token) {
+			return NewBadPattern(token.Span())
+		}
+
+		return NewLiteralPattern(NewLiteral(token.Lexeme, token.Kind, token.Span()).(Literal))
+	case ast.match(lexer.Minus):
+		minus := ast.previous()
+		token := ast.mustConsume(lexer.Number)
+
+		if !ast.checkNumber("-"+token.Lexeme, token) {
+			return NewBadPattern(ast.spanFrom(minus))
+		}
+
+		return NewLiteralPattern(NewLiteral("-"+token.Lexeme, token.Kind, ast.spanFrom(minus)).(Literal))
+	case ast.match(lexer.LeftBracket):
+		return ast.listPattern(ast.matchPattern)
+	case ast.match(lexer.LeftBrace):
+		return ast.mapPattern(ast.matchPattern)
+	}
+
+	if !ast.check(lexer.Identifier) {
+		token := ast.peek()
+		panic(newSyntaxError(fmt.Sprintf("expected pattern, found %s", describeToken(token)), token.Span().Start))
+	}
+
+	name := ast.advance()
+
+	if ast.match(lexer.LeftBrace) {
+		return NewInstancePattern(name.Lexeme, ast.mapPattern(ast.matchPattern), name.Span())
+	}
+
+	return NewIdentifierPattern(name.Lexeme, name.Span())
+}
+
+// Parses a pattern followed by an optional default value, as found within list and map patterns and parameters.
+func (ast *AST) patternWithDefault(pattern Pattern) Pattern {
+	if ast.match(lexer.Equal) {
+		return NewDefaultPattern(pattern, ast.expr())
+	}
+
+	return pattern
+}
+
+// List pattern is built from a comma separated list of patterns enclosed by brackets,
+// optionally ended by a rest name. Nested patterns are parsed by the given element function.
+// It assumes opening bracket is already consumed.
+//
+// [first, [nested], third = 3, ...rest]
+func (ast *AST) listPattern(element func() Pattern) Pattern {
+	bracket := ast.previous()
+	elements := make([]Pattern, 0)
+	var rest Pattern
+
+	for !ast.check(lexer.RightBracket) && !ast.isEnd() {
+		if ast.match(lexer.Ellipsis) {
+			name := ast.mustConsume(lexer.Identifier)
+			rest = NewIdentifierPattern(name.Lexeme, name.Span())
+			ast.mustClose(bracket, lexer.RightBracket)
+
+			return NewListPattern(elements, rest, ast.spanFrom(bracket))
+		}
+
+		elements = append(elements, ast.patternWithDefault(element()))
+
+		if !ast.match(lexer.Comma) {
+			break
+		}
+	}
+
+	ast.mustClose(bracket, lexer.RightBracket, lexer.Comma)
+
+	return NewListPattern(elements, rest, ast.spanFrom(bracket))
+}
+
+// Map pattern is built from a comma separated list of entries enclosed by braces.
+// Each entry is a key, which is either an identifier or a string, followed by the pattern for its value.
+// When the key is an identifier, the pattern can be omitted to bind the value to a variable of the same name.
+// Nested patterns are parsed by the given element function. It assumes opening brace is already consumed.
+//
+// {host, port = 80, server: {name}, "content-type": contentType}
+func (ast *AST) mapPattern(element func() Pattern) MapPattern {
+	brace := ast.previous()
+	entries := make([]MapPatternEntry, 0)
+
+	for !ast.check(lexer.RightBrace) && !ast.isEnd() {
+		var entry MapPatternEntry
+
+		if ast.match(lexer.String) {
+			key := ast.previous()
+			ast.mustConsume(lexer.Colon)
+			entry = MapPatternEntry{key.Lexeme, ast.patternWithDefault(element()), key.Span()}
+		} else {
+			if !ast.check(lexer.Identifier) {
+				panic(ast.unexpected(lexer.Identifier, lexer.String))
+			}
+
+			key := ast.advance()
+
+			if ast.match(lexer.Colon) {
+				entry = MapPatternEntry{key.Lexeme, ast.patternWithDefault(element()), key.Span()}
+			} else {
+				entry = MapPatternEntry{key.Lexeme, ast.patternWithDefault(NewIdentifierPattern(key.Lexeme, key.Span())), key.Span()}
+			}
+		}
+
+		entries = append(entries, entry)
+
+		if !ast.match(lexer.Comma) {
+			break
+		}
+	}
+
+	ast.mustClose(brace, lexer.RightBrace, lexer.Comma)
+
+	return MapPattern{entries, ast.spanFrom(brace)}
+}
+
+// Statement produces an effect. It can be a print, control flow, block or expression statement.
+func (ast *AST) statement() Stmt {
+	if ast.match(lexer.Print) {
+		return ast.printStatement()
+	}
+
+	if ast.match(lexer.If) {
+		return ast.ifStatement()
+	}
+
+	if ast.match(lexer.While) {
+		return ast.whileStatement(nil)
+	}
+
+	if ast.match(lexer.For) {
+		return ast.forStatement(nil)
+	}
+
+	if ast.match(lexer.Return) {
+		return ast.returnStatement()
+	}
+
+	if ast.match(lexer.Break) {
+		keyword := ast.previous()
+		return NewBreakStmt(ast.jumpLabel(keyword), ast.spanFrom(keyword))
+	}
+
+	if ast.match(lexer.Continue) {
+		keyword := ast.previous()
+		return NewContinueStmt(ast.jumpLabel(keyword), ast.spanFrom(keyword))
+	}
+
+	if ast.check(lexer.Identifier) && ast.checkNext(lexer.Colon) {
+		return ast.labeledStatement()
+	}
+
+	// A statement starting with a brace is a block, unless it destructures a map into variables.
+	if ast.check(lexer.LeftBrace) && !ast.isPatternAssignment() {
+		brace := ast.advance()
+		return NewBlockStmt(ast.block(), ast.spanFrom(brace))
+	}
+
+	return ast.expressionStatement()
+}
+
+// Return statement is built from the return keyword and an optional value.
+// It is only allowed within function bodies, and initializers can't return a value.
+func (ast *AST) returnStatement() Stmt {
+	keyword := ast.previous()
+	var value Expr
+
+	if !ast.check(lexer.Semicolon) {
+		value = ast.expr()
+	}
+
+	ast.mustConsume(lexer.Semicolon)
+
+	if ast.function == noFunction {
+		ast.registerError(newSyntaxError("return statement outside of function", keyword.Span().Start))
 	}
 
-	if ast.match(lexer.LeftParen) {
-		expr := ast.mustPrimary() // Recursive call to itself
-		ast.mustConsume(lexer.RightParen)
+	if ast.function == initializer && value != nil {
+		ast.registerError(newSyntaxError("initializer can't return a value", keyword.Span().Start))
+	}
+
+	return NewReturnStmt(value, ast.spanFrom(keyword))
+}
+
+// If statement is built from a parenthesized condition, a then branch and an optional else branch.
+//
+// An else keyword always belongs to the nearest preceding if, so dangling else is
+// resolved by consuming it as soon as then branch is parsed.
+func (ast *AST) ifStatement() Stmt {
+	keyword := ast.previous()
+	paren := ast.mustConsume(lexer.LeftParen)
+	condition := ast.expr()
+	ast.mustClose(paren, lexer.RightParen)
+
+	thenBranch := ast.statement()
+	var elseBranch Stmt
+
+	if ast.match(lexer.Else) {
+		elseBranch = ast.statement()
+	}
+
+	return NewIfStmt(condition, thenBranch, elseBranch, ast.spanFrom(keyword))
+}
+
+// Labeled statement is built from a label, a colon and the loop it names. Only loops can be labeled.
+//
+// outer: for (;;) { while (true) break outer; }
+func (ast *AST) labeledStatement() Stmt {
+	label := ast.advance()
+	ast.advance()
+
+	for _, enclosing := range ast.loops {
+		if enclosing == label.Lexeme {
+			ast.registerError(newSyntaxError(fmt.Sprintf("label '%s' is already used by an enclosing loop", label.Lexeme), label.Span().Start))
+		}
+	}
+
+	switch {
+	case ast.match(lexer.While):
+		return ast.whileStatement(&label)
+	case ast.match(lexer.For):
+		return ast.forStatement(&label)
+	}
+
+	panic(ast.unexpected(lexer.While, lexer.For))
+}
+
+// Parses the optional label of a break or continue statement and the ending semicolon.
+// Both statements are only allowed within loop bodies, and labels must name an enclosing loop.
+func (ast *AST) jumpLabel(keyword lexer.Token) string {
+	var label lexer.Token
+
+	if ast.match(lexer.Identifier) {
+		label = ast.previous()
+	}
+
+	ast.mustConsume(lexer.Semicolon)
+
+	if len(ast.loops) == 0 {
+		ast.registerError(newSyntaxError(fmt.Sprintf("%s statement outside of loop", keyword.Lexeme), keyword.Span().Start))
+	} else if label.Lexeme != "" && !slices.Contains(ast.loops, label.Lexeme) {
+		ast.registerError(newSyntaxError(fmt.Sprintf("undefined label '%s'", label.Lexeme), label.Span().Start))
+	}
+
+	return label.Lexeme
+}
+
+// Parses the body of a loop with the given label, so break and continue statements within it are accepted.
+func (ast *AST) loopBody(label string) Stmt {
+	ast.loops = append(ast.loops, label)
+	defer func() { ast.loops = ast.loops[:len(ast.loops)-1] }()
+
+	return ast.statement()
+}
+
+// Determines the start and the name of a loop, which may be preceded by its label.
+// It assumes loop keyword is already consumed.
+func (ast *AST) loopStart(label *lexer.Token) (lexer.Token, string) {
+	if label == nil {
+		return ast.previous(), ""
+	}
+
+	return *label, label.Lexeme
+}
+
+// While statement is built from a parenthesized condition and the body to repeat.
+func (ast *AST) whileStatement(label *lexer.Token) Stmt {
+	start, name := ast.loopStart(label)
+	paren := ast.mustConsume(lexer.LeftParen)
+	condition := ast.expr()
+	ast.mustClose(paren, lexer.RightParen)
+
+	body := ast.loopBody(name)
+
+	return NewWhileStmt(condition, body, name, ast.spanFrom(start))
+}
+
+// For statement is built from three optional clauses enclosed by parens, and the body to repeat.
+//
+// for (initializer; condition; increment) body
+//
+// Initializer can be either a variable declaration or an expression statement.
+func (ast *AST) forStatement(label *lexer.Token) Stmt {
+	start, name := ast.loopStart(label)
+	paren := ast.mustConsume(lexer.LeftParen)
+
+	var initializer Stmt
+
+	switch {
+	case ast.match(lexer.Semicolon):
+		initializer = nil
+	case ast.match(lexer.Var):
+		initializer = ast.varDeclaration()
+	default:
+		initializer = ast.expressionStatement()
+	}
+
+	var condition Expr
+
+	if !ast.check(lexer.Semicolon) {
+		condition = ast.expr()
+	}
+
+	ast.mustConsume(lexer.Semicolon)
+
+	var increment Expr
+
+	if !ast.check(lexer.RightParen) {
+		increment = ast.expr()
+	}
+
+	ast.mustClose(paren, lexer.RightParen)
+
+	body := ast.loopBody(name)
+
+	return NewForStmt(initializer, condition, increment, body, name, ast.spanFrom(start))
+}
+
+// Block is a sequence of declarations enclosed by braces. It assumes opening brace is already consumed.
+func (ast *AST) block() []Stmt {
+	brace := ast.previous()
+	statements := make([]Stmt, 0)
+
+	for !ast.check(lexer.RightBrace) && !ast.isEnd() {
+		statements = append(statements, ast.statementOrSynchronize())
+	}
+
+	ast.mustClose(brace, lexer.RightBrace)
+
+	return statements
+}
+
+// Print statement is built from the print keyword and the expression to write.
+func (ast *AST) printStatement() Stmt {
+	keyword := ast.previous()
+	expr := ast.expr()
+	ast.mustConsume(lexer.Semicolon)
+
+	return NewPrintStmt(expr, ast.spanFrom(keyword))
+}
+
+// Expression statement is an expression followed by a semicolon.
+// Its result is discarded.
+func (ast *AST) expressionStatement() Stmt {
+	start := ast.peek()
+	expr := ast.expr()
+	ast.mustConsume(lexer.Semicolon)
+
+	return NewExpressionStmt(expr, ast.spanFrom(start))
+}
+
+// Discards tokens until a statement boundary is reached. It allows to keep parsing
+// after a syntax error without reporting cascading errors.
+//
+// A boundary is either the end of a statement (semicolon), a keyword which starts a new one
+// or the closing brace of the enclosing block. Boundaries within braces opened by the discarded
+// statement, whose amount is given, are skipped; closing them ends the statement instead.
+func (ast *AST) synchronize(depth int) {
+	for !ast.isEnd() {
+		kind := ast.peek().Kind
+
+		if depth == 0 && (kind == lexer.RightBrace || startsStatement(kind)) {
+			return
+		}
+
+		ast.advance()
+
+		switch kind {
+		case lexer.LeftBrace:
+			depth++
+		case lexer.RightBrace:
+			if depth--; depth == 0 {
+				return
+			}
+		case lexer.Semicolon:
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// Checks if the token kind is a keyword which starts a statement.
+func startsStatement(kind lexer.TokenKind) bool {
+	switch kind {
+	case lexer.Var, lexer.Class, lexer.Function, lexer.Print, lexer.If, lexer.While, lexer.For, lexer.Return, lexer.Break, lexer.Continue:
+		return true
+	}
+
+	return false
+}
+
+// Checks if current token ends the construct being parsed, because it closes a delimiter, it
+// separates list items or statements, or it starts a new statement. A missing expression or
+// closing token is assumed right before it.
+func (ast *AST) atBoundary() bool {
+	switch ast.peek().Kind {
+	case lexer.Eof, lexer.Semicolon, lexer.Comma, lexer.RightParen, lexer.RightBracket, lexer.RightBrace:
+		return true
+	}
+
+	return startsStatement(ast.peek().Kind)
+}
+
+// Registers the error of a missing token or expression, which is assumed to be in place so parsing
+// goes on. Errors at the same position as the previous one are left aside, since a missing piece
+// often makes the enclosing constructs miss theirs as well.
+func (ast *AST) registerMissing(err syntaxError) {
+	if len(ast.errors) > 0 {
+		if last, ok := ast.errors[len(ast.errors)-1].(syntaxError); ok && last.position == err.position {
+			return
+		}
+	}
+
+	ast.registerError(err)
+}
+
+// Builds a token of the given kind which was missing from source, and is assumed right before current token.
+func (ast *AST) inserted(kind lexer.TokenKind) lexer.Token {
+	position := ast.peek().Span().Start
+	return lexer.CreateTokenAt(kind, lexer.TokenKindToLexemeMap[kind], lexer.Span{Start: position, End: position})
+}
+
+// Checks if a missing token of the given kind can be assumed: only tokens which end constructs
+// (semicolons and closing delimiters), and only when current token is a boundary other than
+// a comma, which rather means the construct goes on.
+func (ast *AST) canInsert(kind lexer.TokenKind) bool {
+	switch kind {
+	case lexer.Semicolon, lexer.RightParen, lexer.RightBracket, lexer.RightBrace:
+		return ast.atBoundary() && !ast.check(lexer.Comma)
+	}
+
+	return false
+}
+
+// Determines if the tokens at current position are a list or map pattern being assigned,
+// instead of a list or map literal. Both start alike, so it looks ahead for an equal sign
+// after the closing bracket or brace.
+func (ast *AST) isPatternAssignment() bool {
+	if !ast.check(lexer.LeftBracket) && !ast.check(lexer.LeftBrace) {
+		return false
+	}
+
+	depth := 0
 
-		return NewGroup(expr)
+	for i := int(ast.current); i < len(ast.tokens); i++ {
+		switch ast.tokens[i].Kind {
+		case lexer.LeftParen, lexer.LeftBracket, lexer.LeftBrace:
+			depth++
+		case lexer.RightParen, lexer.RightBracket, lexer.RightBrace:
+			depth--
+
+			if depth == 0 {
+				return i+1 < len(ast.tokens) && ast.tokens[i+1].Kind == lexer.Equal
+			}
+		case lexer.Eof:
+			return false
+		}
+	}
+
+	return false
+}
+
+// Builds the span which covers from the given token to the last consumed one. When no token
+// was consumed since the given one, like for constructs made only of missing pieces, the span is
+// empty and placed at the start of the given token.
+func (ast *AST) spanFrom(start lexer.Token) lexer.Span {
+	if ast.current == 0 || isBefore(ast.previous().Span().Start, start.Span().Start) {
+		return lexer.Span{Start: start.Span().Start, End: start.Span().Start}
 	}
 
-	panic("uncaught primary expression")
+	return lexer.Join(start.Span(), ast.previous().Span())
+}
+
+// Checks if the first position comes before the second one within source.
+func isBefore(first lexer.Position, second lexer.Position) bool {
+	return first.Line < second.Line || (first.Line == second.Line && first.Column < second.Column)
 }
 
 // Checks if current token matches with the given target, but not advances.
@@ -128,14 +820,57 @@ func (ast *AST) check(kind lexer.TokenKind) bool {
 	return ast.peek().Kind == kind
 }
 
+// Checks if the token after the current one matches with the given target, but not advances.
+func (ast *AST) checkNext(kind lexer.TokenKind) bool {
+	if ast.isEnd() || int(ast.current)+1 >= len(ast.tokens) {
+		return false
+	}
+
+	return ast.tokens[ast.current+1].Kind == kind
+}
+
 // Checks if current token matches with given target, if matches, advance.
-// Else, panics.
+// Else, panics with an error naming the expected and the found tokens.
 func (ast *AST) mustConsume(kind lexer.TokenKind) lexer.Token {
 	if ast.check(kind) {
 		return ast.advance()
 	}
 
-	panic("expected token to consume didn't match with provided at stream")
+	if ast.canInsert(kind) {
+		ast.registerMissing(ast.unexpected(kind))
+		return ast.inserted(kind)
+	}
+
+	panic(ast.unexpected(kind))
+}
+
+// Checks if current token is the closing one of the given opening token, if it is, advance.
+// Else, panics with an error naming the acceptable tokens, which are the given alternatives
+// and the closing one, and pointing to the opening token.
+//
+// expected ',' or ')' to close '(' opened at 3:5, found ';'
+func (ast *AST) mustClose(opening lexer.Token, closing lexer.TokenKind, alternatives ...lexer.TokenKind) lexer.Token {
+	if ast.check(closing) {
+		return ast.advance()
+	}
+
+	token := ast.peek()
+	acceptable := describeKinds(append(alternatives, closing))
+	msg := fmt.Sprintf("expected %s to close %s opened at %s, found %s", acceptable, describeToken(opening), opening.Span().Start, describeToken(token))
+	err := newSyntaxError(msg, token.Span().Start)
+
+	if ast.canInsert(closing) {
+		ast.registerMissing(err)
+		return ast.inserted(closing)
+	}
+
+	panic(err)
+}
+
+// Builds the syntax error for a current token which is none of the acceptable kinds.
+func (ast *AST) unexpected(acceptable ...lexer.TokenKind) syntaxError {
+	token := ast.peek()
+	return newSyntaxError(fmt.Sprintf("expected %s, found %s", describeKinds(acceptable), describeToken(token)), token.Span().Start)
 }
 
 // Consumes the token, returns it and advance.
@@ -171,3 +906,13 @@ func (ast *AST) match(targetKinds ...lexer.TokenKind) bool {
 
 	return false
 }
+
+// Pushes warning into AST's warnings slice.
+func (ast *AST) registerWarning(warning error) {
+	ast.warnings = append(ast.warnings, warning)
+}
+
+// Pushes error into AST's errors slice.
+func (ast *AST) registerError(err error) {
+	ast.errors = append(ast.errors, err)
+}
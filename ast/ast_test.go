@@ -1,9 +1,12 @@
 package ast
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/alfredoprograma/gox/lexer"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAST(t *testing.T) {
@@ -18,35 +21,35 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewLiteral("10", lexer.Number),
+			expected: NewLiteral("10", lexer.Number, lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.True, "true", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewLiteral("true", lexer.True),
+			expected: NewLiteral("true", lexer.True, lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.False, "false", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewLiteral("false", lexer.False),
+			expected: NewLiteral("false", lexer.False, lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.Null, "null", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewLiteral("null", lexer.Null),
+			expected: NewLiteral("null", lexer.Null, lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.String, "Hello world", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewLiteral("Hello world", lexer.String),
+			expected: NewLiteral("Hello world", lexer.String, lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
@@ -55,7 +58,7 @@ func TestAST(t *testing.T) {
 				lexer.MustCreateTokenFromKind(lexer.RightParen, 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewGroup(NewLiteral("Grouping expr", lexer.String)),
+			expected: NewGroup(NewLiteral("Grouping expr", lexer.String, lexer.Span{}), lexer.Span{}),
 		},
 		{
 			tokens: []lexer.Token{
@@ -63,7 +66,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "12", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewUnary(lexer.Minus, NewLiteral("12", lexer.Number)),
+			expected: NewUnary(lexer.MustCreateTokenFromKind(lexer.Minus, 1), NewLiteral("12", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -72,7 +75,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "5", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("5", lexer.Number), lexer.Star, NewLiteral("5", lexer.Number)),
+			expected: NewBinary(NewLiteral("5", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Star, 1), NewLiteral("5", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -81,7 +84,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "5", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("5", lexer.Number), lexer.Slash, NewLiteral("5", lexer.Number)),
+			expected: NewBinary(NewLiteral("5", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Slash, 1), NewLiteral("5", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -90,7 +93,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("10", lexer.Number), lexer.Plus, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("10", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Plus, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -99,7 +102,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("10", lexer.Number), lexer.Minus, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("10", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Minus, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -108,7 +111,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("14", lexer.Number), lexer.Greater, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("14", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Greater, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -117,7 +120,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("14", lexer.Number), lexer.GreaterEqual, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("14", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.GreaterEqual, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		}, {
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.Number, "9", 1),
@@ -125,7 +128,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("9", lexer.Number), lexer.Less, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("9", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Less, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		}, {
 			tokens: []lexer.Token{
 				lexer.CreateToken(lexer.Number, "9", 1),
@@ -133,7 +136,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "10", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("9", lexer.Number), lexer.LessEqual, NewLiteral("10", lexer.Number)),
+			expected: NewBinary(NewLiteral("9", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.LessEqual, 1), NewLiteral("10", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -142,7 +145,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "7", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("7", lexer.Number), lexer.DoubleEqual, NewLiteral("7", lexer.Number)),
+			expected: NewBinary(NewLiteral("7", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.DoubleEqual, 1), NewLiteral("7", lexer.Number, lexer.Span{})),
 		},
 		{
 			tokens: []lexer.Token{
@@ -151,7 +154,7 @@ func TestAST(t *testing.T) {
 				lexer.CreateToken(lexer.Number, "7", 1),
 				lexer.MustCreateTokenFromKind(lexer.Eof, 1),
 			},
-			expected: NewBinary(NewLiteral("7", lexer.Number), lexer.BangEqual, NewLiteral("7", lexer.Number)),
+			expected: NewBinary(NewLiteral("7", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.BangEqual, 1), NewLiteral("7", lexer.Number, lexer.Span{})),
 		},
 	}
 
@@ -164,3 +167,351 @@ func TestAST(t *testing.T) {
 		}
 	}
 }
+
+func TestParseProgram(t *testing.T) {
+	t.Run("should parse a sequence of expression statements", func(t *testing.T) {
+		program, errs := Parse("1 + 2 * 3; (4 - 1) / 3;")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "(1 + (2 * 3));\n(((4 - 1)) / 3);", program.String())
+	})
+
+	t.Run("should report syntax errors and keep parsing next statements", func(t *testing.T) {
+		program, errs := Parse("1 + ; 2 * 3;")
+
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "(1 + <bad expression>);\n(2 * 3);", program.String())
+	})
+
+	t.Run("should parse right associative assignments", func(t *testing.T) {
+		program, errs := Parse("a = b = 1 + 2;")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "(a = (b = (1 + 2)));", program.String())
+	})
+
+	t.Run("should report invalid assignment targets", func(t *testing.T) {
+		_, errs := Parse("1 = 2;")
+
+		assert.Equal(t, []error{newSyntaxError("invalid assignment target (1)", lexer.Position{Line: 1, Column: 3})}, errs)
+	})
+
+	t.Run("should parse right associative conditionals below logical or", func(t *testing.T) {
+		program, errs := Parse("a = x or y ? 1 : z ? 2 : 3;")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "(a = ((x or y) ? 1 : (z ? 2 : 3)));", program.String())
+	})
+
+	t.Run("should parse lists, indexes and slices", func(t *testing.T) {
+		program, errs := Parse("[1, [2]][0] = xs[a ? 1 : 2][1:][:-1][:];")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "([1, [2]][0] = xs[(a ? 1 : 2)][1:][:(-1)][:]);", program.String())
+	})
+
+	t.Run("should parse destructuring patterns", func(t *testing.T) {
+		program, errs := Parse(`
+			var [a, [b], c = 1, ...rest] = xs;
+			var {host, port = 80, server: {name}, "content-type": type, "if": x} = config;
+			[a, b] = [b, a];
+			{host} = config;
+			{ print host; }
+			function f([x], {y}) {}
+		`)
+
+		assert.Empty(t, errs)
+		assert.Equal(t, strings.Join([]string{
+			"var [a, [b], c = 1, ...rest] = xs;",
+			`var {host, port = 80, server: {name}, "content-type": type, "if": x} = config;`,
+			"([a, b] = [b, a]);",
+			"({host} = config);",
+			"{ print host; }",
+			"function f([x], {y}) {}",
+		}, "\n"), program.String())
+	})
+
+	t.Run("should parse match expressions", func(t *testing.T) {
+		program, errs := Parse(`match x { 1 | -2 => "a", [y, ...z] if y => y, {k: "v"} => 1, User {name} => name, _ => null };`)
+
+		assert.Empty(t, errs)
+		assert.Empty(t, program.Warnings)
+		assert.Equal(t, `match x { 1 | -2 => a, [y, ...z] if y => y, {k: "v"} => 1, User {name} => name, _ => null };`, program.String())
+	})
+
+	t.Run("should warn about match expressions which can fail or have unreachable arms", func(t *testing.T) {
+		program, errs := Parse(`
+			match x { 1 => 1 };
+			match 3 { 1 | 2 => 1, [a] => a };
+			match x { y => 1, _ => 2 };
+		`)
+
+		assert.Empty(t, errs)
+		assert.Equal(t, []error{
+			newWarning("match is not exhaustive, add a wildcard (_) arm", lexer.Position{Line: 2, Column: 4}),
+			newWarning("no match arm can match value 3", lexer.Position{Line: 3, Column: 4}),
+			newWarning("unreachable match arm (_)", lexer.Position{Line: 4, Column: 22}),
+		}, program.Warnings)
+	})
+
+	t.Run("should group operators by precedence and associativity", func(t *testing.T) {
+		program, errs := Parse("1 - 2 - 3 * 4 / 5; a = b = !c == d or e and f; -xs[0].y(1);")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "((1 - 2) - ((3 * 4) / 5));\n(a = (b = (((!c) == d) or (e and f))));\n(-xs[0].y(1));", program.String())
+	})
+
+	t.Run("should report expected tokens and where unclosed delimiters were opened", func(t *testing.T) {
+		testCases := map[string]string{
+			"print (1 + 2;":                 "[AST]: expected ')' to close '(' opened at 1:7, found ';' at 1:13",
+			"f(a, b;":                       "[AST]: expected ',' or ')' to close '(' opened at 1:2, found ';' at 1:7",
+			"var xs = [1, 2;":               "[AST]: expected ',' or ']' to close '[' opened at 1:10, found ';' at 1:15",
+			"xs[1 2];":                      "[AST]: expected ':' or ']' to close '[' opened at 1:3, found '2' at 1:6",
+			"if (x {}":                      "[AST]: expected ')' to close '(' opened at 1:4, found '{' at 1:7",
+			"function f(a b) {}":            "[AST]: expected ',' or ')' to close '(' opened at 1:11, found 'b' at 1:14",
+			"while (true) {\n\tprint 1;\n":  "[AST]: expected '}' to close '{' opened at 1:14, found end of file at 3:1",
+			"class A { f() {} 1 }":          "[AST]: expected identifier or '}' to close '{' opened at 1:9, found '1' at 1:18",
+			"match x { 1 2 => 3 };":         "[AST]: expected '|', 'if' or '=>', found '2' at 1:13",
+			"var {1: a} = m;":               "[AST]: expected identifier or string, found '1' at 1:6",
+			"var 1 = 2;":                    "[AST]: expected identifier, '[' or '{', found '1' at 1:5",
+			"print x;\nvar y = 2\nprint y;": "[AST]: expected ';', found 'print' at 3:1",
+			"match x { = => 1 };":           "[AST]: expected pattern, found '=' at 1:11",
+		}
+
+		for source, expected := range testCases {
+			_, errs := Parse(source)
+
+			if assert.NotEmpty(t, errs, source) {
+				assert.EqualError(t, errs[0], expected, source)
+			}
+		}
+	})
+
+	t.Run("should parse optional chains and null coalescing", func(t *testing.T) {
+		program, errs := Parse("a?.b.c?.(1)?.[0]?.[1:]; x ?? y or z ?? w; c ? x ?? y : z;")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "a?.b.c?.(1)?.[0]?.[1:];\n((x ?? (y or z)) ?? w);\n(c ? (x ?? y) : z);", program.String())
+	})
+
+	t.Run("should reject assignments to optional chains", func(t *testing.T) {
+		_, errs := Parse("a?.b = 1; a?.[0] = 1; a?.;")
+
+		assert.Equal(t, []error{
+			newSyntaxError("invalid assignment target (a?.b)", lexer.Position{Line: 1, Column: 6}),
+			newSyntaxError("invalid assignment target (a?.[0])", lexer.Position{Line: 1, Column: 18}),
+			newSyntaxError("expected identifier, '(' or '[', found ';'", lexer.Position{Line: 1, Column: 26}),
+		}, errs)
+	})
+
+	t.Run("should parse rest parameters and spread arguments", func(t *testing.T) {
+		program, errs := Parse("function log(level, ...parts) {} var f = (...xs) => xs; log(...a, 1); [...a, ...b];")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "function log(level, ...parts) {}\nvar f = ((...xs) => xs);\nlog(...a, 1);\n[...a, ...b];", program.String())
+	})
+
+	t.Run("should only accept rest parameter at the end", func(t *testing.T) {
+		_, errs := Parse("function f(...a, b) {} function g(...) {}")
+
+		assert.Equal(t, []error{
+			newSyntaxError("expected ')' to close '(' opened at 1:11, found ','", lexer.Position{Line: 1, Column: 16}),
+			newSyntaxError("expected identifier, found ')'", lexer.Position{Line: 1, Column: 38}),
+		}, errs)
+	})
+
+	t.Run("should parse default parameters and named arguments", func(t *testing.T) {
+		program, errs := Parse(`function connect(host, port = 5432, tls = true) {} connect("db", tls: a ? b : c);`)
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "function connect(host, port = 5432, tls = true) {}\nconnect(db, tls: (a ? b : c));", program.String())
+	})
+
+	t.Run("should reject required parameters and positional arguments after optional ones", func(t *testing.T) {
+		_, errs := Parse("function f(a = 1, b) {} f(a: 1, 2, ...xs);")
+
+		assert.Equal(t, []error{
+			newSyntaxError("parameter without default value can't follow parameters with default value", lexer.Position{Line: 1, Column: 19}),
+			newSyntaxError("positional argument can't follow named arguments", lexer.Position{Line: 1, Column: 33}),
+			newSyntaxError("positional argument can't follow named arguments", lexer.Position{Line: 1, Column: 36}),
+		}, errs)
+	})
+
+	t.Run("should parse break, continue and labeled loops", func(t *testing.T) {
+		program, errs := Parse("outer: for (;;) { while (true) { if (a) break outer; continue; } break; }")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "outer: for (; ; ) { while (true) { if (a) break outer; continue; } break; }", program.String())
+	})
+
+	t.Run("should reject break and continue outside of loops", func(t *testing.T) {
+		_, errs := Parse("break; while (true) { function f() { continue; } } a: while (true) { break b; a: for (;;) {} } b: print 1;")
+
+		assert.Equal(t, []error{
+			newSyntaxError("break statement outside of loop", lexer.Position{Line: 1, Column: 1}),
+			newSyntaxError("continue statement outside of loop", lexer.Position{Line: 1, Column: 38}),
+			newSyntaxError("undefined label 'b'", lexer.Position{Line: 1, Column: 76}),
+			newSyntaxError("label 'a' is already used by an enclosing loop", lexer.Position{Line: 1, Column: 79}),
+			newSyntaxError("expected 'while' or 'for', found 'print'", lexer.Position{Line: 1, Column: 99}),
+		}, errs)
+	})
+
+	t.Run("should build complete trees from incomplete source", func(t *testing.T) {
+		program, errs := Parse("var x = (1 + ;\nprint [a,\nprint f(x")
+
+		assert.Equal(t, []error{
+			newSyntaxError("expected expression, found ';'", lexer.Position{Line: 1, Column: 14}),
+			newSyntaxError("expected expression, found 'print'", lexer.Position{Line: 3, Column: 1}),
+			newSyntaxError("expected ',' or ')' to close '(' opened at 3:8, found end of file", lexer.Position{Line: 3, Column: 10}),
+		}, errs)
+		assert.Equal(t, "var x = ((1 + <bad expression>));\nprint [a, <bad expression>];\nprint f(x);", program.String())
+
+		missing := program.Statements[0].(VarStmt).Initializer().(Group).Expr().(Binary).Right()
+		assert.Equal(t, NewBadExpr(lexer.Span{Start: lexer.Position{Line: 1, Column: 14}, End: lexer.Position{Line: 1, Column: 14}}), missing)
+	})
+
+	t.Run("should build trees from source starting with a closing delimiter", func(t *testing.T) {
+		for _, source := range []string{")", "}", "]"} {
+			program, errs := Parse(source)
+
+			assert.Equal(t, []error{
+				newSyntaxError(fmt.Sprintf("expected expression, found '%s'", source), lexer.Position{Line: 1, Column: 1}),
+			}, errs)
+			assert.Equal(t, []Stmt{NewBadStmt(lexer.Span{Start: lexer.Position{Line: 1, Column: 1}, End: lexer.Position{Line: 1, Column: 2}})}, program.Statements)
+		}
+	})
+
+	t.Run("should replace numbers out of range by placeholders", func(t *testing.T) {
+		huge := strings.Repeat("9", 400)
+		program, errs := Parse("var x = " + huge + ";\nprint match x { -" + huge + " => 1, _ => 2 };")
+
+		assert.Equal(t, []error{
+			newSyntaxError("number is out of range", lexer.Position{Line: 1, Column: 9}),
+			newSyntaxError("number is out of range", lexer.Position{Line: 2, Column: 18}),
+		}, errs)
+		assert.Equal(t, "var x = <bad expression>;\nprint match x { <bad pattern> => 1, _ => 2 };", program.String())
+		assert.Equal(t, NewBadExpr(lexer.Span{Start: lexer.Position{Line: 1, Column: 9}, End: lexer.Position{Line: 1, Column: 409}}), program.Statements[0].(VarStmt).Initializer())
+	})
+
+	t.Run("should replace statements which can't be completed by placeholders", func(t *testing.T) {
+		program, errs := Parse("class 1 { m() {} }\n{ print x y; print 2; }\n}\nprint 3;")
+
+		assert.Equal(t, []error{
+			newSyntaxError("expected identifier, found '1'", lexer.Position{Line: 1, Column: 7}),
+			newSyntaxError("expected ';', found 'y'", lexer.Position{Line: 2, Column: 11}),
+			newSyntaxError("expected expression, found '}'", lexer.Position{Line: 3, Column: 1}),
+		}, errs)
+		assert.Equal(t, "<bad statement>\n{ <bad statement> print 2; }\n<bad statement>\nprint 3;", program.String())
+		assert.Equal(t, lexer.Span{Start: lexer.Position{Line: 1, Column: 1}, End: lexer.Position{Line: 1, Column: 19}}, program.Statements[0].Span())
+		assert.Equal(t, lexer.Span{Start: lexer.Position{Line: 2, Column: 3}, End: lexer.Position{Line: 2, Column: 13}}, program.Statements[1].(BlockStmt).Statements()[0].Span())
+	})
+
+	t.Run("should report missing expressions naming the found token", func(t *testing.T) {
+		_, errs := Parse("var x = 1 + ;\nprint *;\nprint 1 +")
+
+		assert.Equal(t, []error{
+			newSyntaxError("expected expression, found ';'", lexer.Position{Line: 1, Column: 13}),
+			newSyntaxError("expected expression, found '*'", lexer.Position{Line: 2, Column: 7}),
+			newSyntaxError("expected expression, found end of file", lexer.Position{Line: 3, Column: 10}),
+		}, errs)
+	})
+
+	t.Run("should record source spans of nodes", func(t *testing.T) {
+		program, errs := Parse("var total = price * (1 + rate);\nprint xs[0].name;")
+		span := func(startLine, startColumn, endLine, endColumn uint) lexer.Span {
+			return lexer.Span{Start: lexer.Position{Line: startLine, Column: startColumn}, End: lexer.Position{Line: endLine, Column: endColumn}}
+		}
+
+		assert.Empty(t, errs)
+
+		declaration := program.Statements[0].(VarStmt)
+		product := declaration.Initializer().(Binary)
+
+		assert.Equal(t, span(1, 1, 1, 32), declaration.Span())
+		assert.Equal(t, span(1, 5, 1, 10), declaration.Target().Span())
+		assert.Equal(t, span(1, 13, 1, 31), product.Span())
+		assert.Equal(t, span(1, 19, 1, 20), product.Operator().Span())
+		assert.Equal(t, span(1, 21, 1, 31), product.Right().Span())
+
+		get := program.Statements[1].(PrintStmt).Expr().(Get)
+
+		assert.Equal(t, span(2, 1, 2, 18), program.Statements[1].Span())
+		assert.Equal(t, span(2, 7, 2, 17), get.Span())
+		assert.Equal(t, span(2, 9, 2, 12), get.Object().(Index).Brackets())
+		assert.Equal(t, span(1, 1, 2, 18), program.Span())
+	})
+
+	t.Run("should parse chained calls", func(t *testing.T) {
+		program, errs := Parse("f(1, g(2))(3)();")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "f(1, g(2))(3)();", program.String())
+	})
+
+	t.Run("should disambiguate arrow functions from groups", func(t *testing.T) {
+		program, errs := Parse("(a) + (b) => a * b; map(xs, function (x) { return x; });")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "((a) + ((b) => (a * b)));\nmap(xs, function (x) { return x; });", program.String())
+	})
+
+	t.Run("should parse class declarations", func(t *testing.T) {
+		program, errs := Parse("class A { init(x) { this.x = x; } get() { return this.x; } } A(1).get();")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "class A { init(x) { (this.x = x); } get() { return this.x; } }\nA(1).get();", program.String())
+	})
+
+	t.Run("should parse subclasses and super calls", func(t *testing.T) {
+		program, errs := Parse("class B < A { f() { return super.f(); } }")
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "class B < A { f() { return super.f(); } }", program.String())
+	})
+
+	t.Run("should report invalid inheritance and super usages", func(t *testing.T) {
+		_, errs := Parse("class A < A {} class B { f() { super.f(); } } super.f();")
+
+		assert.Equal(t, []error{
+			newSyntaxError("class A can't inherit from itself", lexer.Position{Line: 1, Column: 11}),
+			newSyntaxError("can't use super in a class without superclass", lexer.Position{Line: 1, Column: 32}),
+			newSyntaxError("can't use super outside of a method", lexer.Position{Line: 1, Column: 47}),
+		}, errs)
+	})
+
+	t.Run("should report this outside of methods", func(t *testing.T) {
+		_, errs := Parse("function f() { return this; }")
+
+		assert.Equal(t, []error{newSyntaxError("can't use this outside of a method", lexer.Position{Line: 1, Column: 23})}, errs)
+	})
+
+	t.Run("should report values returned from initializers", func(t *testing.T) {
+		_, errs := Parse("class A { init() { return 1; } }")
+
+		assert.Equal(t, []error{newSyntaxError("initializer can't return a value", lexer.Position{Line: 1, Column: 20})}, errs)
+	})
+
+	t.Run("should report return statements outside of functions", func(t *testing.T) {
+		_, errs := Parse("return 1;")
+
+		assert.Equal(t, []error{newSyntaxError("return statement outside of function", lexer.Position{Line: 1, Column: 1})}, errs)
+	})
+
+	t.Run("should parse a single expression", func(t *testing.T) {
+		l := lexer.New("-(1 + 2) >= 3")
+		tokens, _ := l.Tokenize()
+		expr, errs := ParseExpr(tokens)
+
+		assert.Empty(t, errs)
+		assert.Equal(t, "((-((1 + 2))) >= 3)", expr.String())
+	})
+
+	t.Run("should report trailing tokens after a single expression", func(t *testing.T) {
+		l := lexer.New("1 2")
+		tokens, _ := l.Tokenize()
+		expr, errs := ParseExpr(tokens)
+
+		assert.Nil(t, expr)
+		assert.Len(t, errs, 1)
+	})
+}
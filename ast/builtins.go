@@ -0,0 +1,104 @@
+package ast
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// A function implemented natively by the runtime.
+type Native struct {
+	name  string
+	arity int
+	fn    func(args []any) (any, error)
+}
+
+func newNative(name string, arity int, fn func(args []any) (any, error)) *Native {
+	return &Native{name, arity, fn}
+}
+
+// Natives expect a fixed amount of arguments, which can't be passed by name.
+func (n *Native) Signature() Signature {
+	return Signature{Params: make([]string, n.arity), Required: n.arity}
+}
+
+func (n *Native) Call(args []any) (any, error) {
+	return n.fn(args)
+}
+
+func (n *Native) String() string {
+	return fmt.Sprintf("<native function %s>", n.name)
+}
+
+// Builds the native functions available at the global scope of every program.
+func builtins() []*Native {
+	return []*Native{
+		newNative("len", 1, builtinLen),
+		newNative("keys", 1, builtinKeys),
+		newNative("has", 2, builtinHas),
+		newNative("delete", 2, builtinDelete),
+	}
+}
+
+// Returns the amount of elements of a list or map, or the amount of characters of a string.
+func builtinLen(args []any) (any, error) {
+	switch value := args[0].(type) {
+	case *List:
+		return float64(len(value.elements)), nil
+	case *Map:
+		return float64(len(value.keys)), nil
+	case string:
+		return float64(utf8.RuneCountInString(value)), nil
+	default:
+		return nil, createASTError(fmt.Sprintf("len expects a list, map or string, got value of type %s", typeName(value)))
+	}
+}
+
+// Returns a new list with the keys of a map, in insertion order.
+func builtinKeys(args []any) (any, error) {
+	m, err := expectMap("keys", args[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]any, len(m.keys))
+	copy(keys, m.keys)
+
+	return newList(keys), nil
+}
+
+// Checks if a map contains the given key.
+func builtinHas(args []any) (any, error) {
+	m, err := expectMap("has", args[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m.has(args[1]), nil
+}
+
+// Removes the given key from a map. It results into true if key existed.
+func builtinDelete(args []any) (any, error) {
+	m, err := expectMap("delete", args[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	existed := m.has(args[1])
+	m.delete(args[1])
+
+	return existed, nil
+}
+
+// Asserts the argument received by a native function is a map.
+func expectMap(name string, value any) (*Map, error) {
+	m, ok := value.(*Map)
+
+	if !ok {
+		return nil, createASTError(fmt.Sprintf("%s expects a map, got value of type %s", name, typeName(value)))
+	}
+
+	return m, nil
+}
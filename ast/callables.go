@@ -0,0 +1,223 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// A runtime value which can be invoked through a call expression.
+type Callable interface {
+	Signature() Signature // Describes the arguments accepted by the callable.
+	// Invokes the callable with already computed arguments, placed at the position of their parameters.
+	// Optional parameters may be left without argument, either missing at the end or as missingArgument.
+	Call(args []any) (any, error)
+}
+
+// Describes the arguments accepted by a callable.
+type Signature struct {
+	Params   []string // name of each parameter, empty when it is destructured; rest parameter is left aside
+	Required int      // amount of leading parameters without default value
+	Variadic bool     // whether arguments beyond the parameters are collected into a rest one
+}
+
+// Placeholder for the argument of an optional parameter, skipped by named arguments after it.
+type missingArgument struct{}
+
+// Checks if the parameter at the given index has no argument.
+func isMissing(args []any, index int) bool {
+	if index >= len(args) {
+		return true
+	}
+
+	_, ok := args[index].(missingArgument)
+	return ok
+}
+
+// An argument passed by the name of its parameter.
+type namedValue struct {
+	name     string
+	value    any
+	position lexer.Position
+}
+
+// Places named arguments at the position of their parameters, after the positional ones,
+// and checks every required parameter gets an argument.
+func resolveArguments(callable Callable, positional []any, named []namedValue) ([]any, error) {
+	signature := callable.Signature()
+
+	if len(positional) > len(signature.Params) && !signature.Variadic {
+		return nil, arityError(callable, len(positional)+len(named))
+	}
+
+	args := positional
+
+	for _, arg := range named {
+		index := slices.Index(signature.Params, arg.name)
+
+		if index == -1 {
+			return nil, createASTErrorAt(fmt.Sprintf("%s has no parameter named '%s'", stringify(callable), arg.name), arg.position)
+		}
+
+		if !isMissing(args, index) {
+			return nil, createASTErrorAt(fmt.Sprintf("%s got multiple values for parameter '%s'", stringify(callable), arg.name), arg.position)
+		}
+
+		for len(args) <= index {
+			args = append(args, missingArgument{})
+		}
+
+		args[index] = arg.value
+	}
+
+	for i := 0; i < signature.Required; i++ {
+		if !isMissing(args, i) {
+			continue
+		}
+
+		if len(named) == 0 || signature.Params[i] == "" {
+			return nil, arityError(callable, len(positional)+len(named))
+		}
+
+		return nil, createASTError(fmt.Sprintf("%s expected an argument for parameter '%s'", stringify(callable), signature.Params[i]))
+	}
+
+	return args, nil
+}
+
+// Builds the error reported when the amount of arguments is not accepted by the callable.
+func arityError(callable Callable, args int) error {
+	signature := callable.Signature()
+	expected := strconv.Itoa(len(signature.Params))
+
+	if signature.Variadic {
+		expected = fmt.Sprintf("at least %d", signature.Required)
+	} else if signature.Required < len(signature.Params) {
+		expected = fmt.Sprintf("%d to %d", signature.Required, len(signature.Params))
+	}
+
+	return createASTError(fmt.Sprintf("%s expected %s arguments but got %d", stringify(callable), expected, args))
+}
+
+// A user defined function, either declared or anonymous. It keeps the environment where it was declared,
+// so it can access to the variables in scope at that point (closure).
+type Function struct {
+	name          string
+	params        []Pattern
+	body          []Stmt
+	closure       *Environment
+	isInitializer bool // class initializers always result into the instance
+}
+
+func newFunction(name string, params []Pattern, body []Stmt, closure *Environment, isInitializer bool) *Function {
+	return &Function{name, params, body, closure, isInitializer}
+}
+
+// Parameters are named after their identifier, destructured ones are unnamed.
+func (f *Function) Signature() Signature {
+	signature := Signature{Params: make([]string, 0, len(f.params))}
+
+	for _, param := range f.params {
+		switch p := param.(type) {
+		case RestPattern:
+			signature.Variadic = true
+			continue
+		case DefaultPattern:
+			param = p.pattern
+		default:
+			signature.Required++
+		}
+
+		name := ""
+
+		if identifier, ok := param.(IdentifierPattern); ok {
+			name = identifier.name
+		}
+
+		signature.Params = append(signature.Params, name)
+	}
+
+	return signature
+}
+
+// Executes function body within a new environment nested into its closure,
+// where each parameter pattern is bound to its corresponding argument.
+//
+// Default values of parameters without argument are computed at call time, within the new environment,
+// so they can refer to previous parameters. Rest parameter is bound to a new list with the remaining arguments.
+func (f *Function) Call(args []any) (any, error) {
+	env := NewEnvironment(f.closure)
+
+	for i, param := range f.params {
+		var err error
+
+		switch p := param.(type) {
+		case RestPattern:
+			rest := make([]any, 0)
+
+			if i < len(args) {
+				rest = append(rest, args[i:]...)
+			}
+
+			err = p.bind(env, newList(rest), declareInto(env))
+		case DefaultPattern:
+			if isMissing(args, i) {
+				err = p.bindDefault(env, declareInto(env))
+			} else {
+				err = p.bind(env, args[i], declareInto(env))
+			}
+		default:
+			err = param.bind(env, args[i], declareInto(env))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := executeBlock(f.body, env)
+	signal, isReturn := err.(returnSignal)
+
+	if err != nil && !isReturn {
+		return nil, err
+	}
+
+	if f.isInitializer {
+		return f.closure.Get(THIS_NAME)
+	}
+
+	if isReturn {
+		return signal.value, nil
+	}
+
+	return nil, nil
+}
+
+// Creates a copy of the method whose closure binds this to the given instance.
+func (f *Function) bind(instance *Instance) *Function {
+	env := NewEnvironment(f.closure)
+	env.Define(THIS_NAME, instance)
+
+	return newFunction(f.name, f.params, f.body, env, f.isInitializer)
+}
+
+func (f *Function) String() string {
+	if f.name == "" {
+		return "<anonymous function>"
+	}
+
+	return fmt.Sprintf("<function %s>", f.name)
+}
+
+// Unwinds the execution of a function body up to its call when a return statement is reached.
+//
+// It is propagated as an error through the statements, but it never escapes from a function call.
+type returnSignal struct {
+	value any
+}
+
+func (r returnSignal) Error() string {
+	return fmt.Sprintf("%s: return statement outside of function", AST_PREFIX)
+}
@@ -0,0 +1,107 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// Name of the method which initializes new instances of a class.
+const INITIALIZER_NAME = "init"
+
+// Name bound to the current instance within methods.
+const THIS_NAME = "this"
+
+// Name bound to the superclass within methods of a subclass.
+const SUPER_NAME = "super"
+
+// A user defined class. Calling it creates a new instance and runs its initializer, if any.
+type Class struct {
+	name       string
+	superclass *Class // nil when class doesn't inherit from any other class
+	methods    map[string]*Function
+}
+
+func newClass(name string, superclass *Class, methods map[string]*Function) *Class {
+	return &Class{name, superclass, methods}
+}
+
+// Looks up a method by name through the superclasses chain, so overridden methods take precedence.
+func (c *Class) findMethod(name string) (*Function, bool) {
+	if method, ok := c.methods[name]; ok {
+		return method, true
+	}
+
+	if c.superclass != nil {
+		return c.superclass.findMethod(name)
+	}
+
+	return nil, false
+}
+
+// Arguments expected by the initializer. Classes without initializer don't expect any argument.
+func (c *Class) Signature() Signature {
+	if initializer, ok := c.findMethod(INITIALIZER_NAME); ok {
+		return initializer.Signature()
+	}
+
+	return Signature{}
+}
+
+func (c *Class) Call(args []any) (any, error) {
+	instance := newInstance(c)
+
+	if initializer, ok := c.findMethod(INITIALIZER_NAME); ok {
+		if _, err := initializer.bind(instance).Call(args); err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func (c *Class) String() string {
+	return fmt.Sprintf("<class %s>", c.name)
+}
+
+// An object created from a class. It holds its own fields and shares methods through its class.
+type Instance struct {
+	class  *Class
+	fields map[string]any
+}
+
+func newInstance(class *Class) *Instance {
+	return &Instance{class, make(map[string]any)}
+}
+
+// Checks if the instance was created from the given class or from any of its subclasses.
+func (i *Instance) isInstanceOf(class *Class) bool {
+	for current := i.class; current != nil; current = current.superclass {
+		if current == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Looks up a property by name. Fields shadow methods; methods are bound to the instance,
+// so they can be used as standalone values.
+func (i *Instance) Get(name string) (any, error) {
+	if value, ok := i.fields[name]; ok {
+		return value, nil
+	}
+
+	if method, ok := i.class.findMethod(name); ok {
+		return method.bind(i), nil
+	}
+
+	return nil, createASTError(fmt.Sprintf("undefined property '%s' on %s", name, i.String()))
+}
+
+// Binds the value to the given field name, creating the field if it doesn't exist.
+func (i *Instance) Set(name string, value any) {
+	i.fields[name] = value
+}
+
+func (i *Instance) String() string {
+	return fmt.Sprintf("<%s instance>", i.class.name)
+}
@@ -0,0 +1,213 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// A mutable and ordered sequence of runtime values.
+type List struct {
+	elements []any
+}
+
+func newList(elements []any) *List {
+	return &List{elements}
+}
+
+// Reads the element at the given index. Negative indexes count backwards from the end.
+func (l *List) get(index any, position lexer.Position) (any, error) {
+	i, err := l.resolveIndex(index, position)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return l.elements[i], nil
+}
+
+// Replaces the element at the given index. Negative indexes count backwards from the end.
+func (l *List) set(index any, value any, position lexer.Position) error {
+	i, err := l.resolveIndex(index, position)
+
+	if err != nil {
+		return err
+	}
+
+	l.elements[i] = value
+	return nil
+}
+
+// Creates a new list from the elements between start (inclusive) and end (exclusive) indexes.
+//
+// Bounds are optional (nil) and may be negative. Unlike indexing, out of range bounds are
+// clamped to the list length instead of failing.
+func (l *List) slice(start any, end any, position lexer.Position) (*List, error) {
+	length := len(l.elements)
+	from, err := resolveSliceBound(start, 0, length, position)
+
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := resolveSliceBound(end, length, length, position)
+
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]any, 0)
+
+	if from < to {
+		elements = append(elements, l.elements[from:to]...)
+	}
+
+	return newList(elements), nil
+}
+
+// Transforms a runtime index into a valid position within the list.
+func (l *List) resolveIndex(index any, position lexer.Position) (int, error) {
+	i, err := toInteger(index, position)
+
+	if err != nil {
+		return 0, err
+	}
+
+	length := len(l.elements)
+
+	if i < 0 {
+		i += length
+	}
+
+	if i < 0 || i >= length {
+		return 0, createASTErrorAt(fmt.Sprintf("index %s out of bounds for list of length %d", stringify(index), length), position)
+	}
+
+	return i, nil
+}
+
+func (l *List) String() string {
+	elements := make([]string, len(l.elements))
+
+	for i, element := range l.elements {
+		elements[i] = stringifyNested(element)
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+// Transforms an optional slice bound into a position clamped between zero and the length.
+// When bound is nil, fallback position is used.
+func resolveSliceBound(bound any, fallback int, length int, position lexer.Position) (int, error) {
+	if bound == nil {
+		return fallback, nil
+	}
+
+	i, err := toInteger(bound, position)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if i < 0 {
+		i += length
+	}
+
+	return max(0, min(i, length)), nil
+}
+
+// Transforms a runtime value into an integer, which is required to index collections.
+func toInteger(value any, position lexer.Position) (int, error) {
+	number, ok := value.(float64)
+
+	if !ok || number != math.Trunc(number) {
+		return 0, createASTErrorAt(fmt.Sprintf("index must be an integer number, got %s", stringify(value)), position)
+	}
+
+	return int(number), nil
+}
+
+// A mutable collection of values indexed by keys, which remembers keys insertion order.
+//
+// Only strings, numbers and booleans are allowed as keys.
+type Map struct {
+	keys   []any
+	values map[any]any
+}
+
+func newMap() *Map {
+	return &Map{make([]any, 0), make(map[any]any)}
+}
+
+// Reads the value bound to the given key. Reading a missing key is an error.
+func (m *Map) get(key any, position lexer.Position) (any, error) {
+	if err := validateKey(key, position); err != nil {
+		return nil, err
+	}
+
+	value, ok := m.values[key]
+
+	if !ok {
+		return nil, createASTErrorAt(fmt.Sprintf("undefined key %s", stringifyNested(key)), position)
+	}
+
+	return value, nil
+}
+
+// Binds the value to the given key. New keys are placed after the existing ones.
+func (m *Map) set(key any, value any, position lexer.Position) error {
+	if err := validateKey(key, position); err != nil {
+		return err
+	}
+
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+
+	m.values[key] = value
+	return nil
+}
+
+// Checks if the given key is bound to any value.
+func (m *Map) has(key any) bool {
+	_, ok := m.values[key]
+	return ok
+}
+
+// Removes the given key and its value. Deleting a missing key does nothing.
+func (m *Map) delete(key any) {
+	if !m.has(key) {
+		return
+	}
+
+	delete(m.values, key)
+
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *Map) String() string {
+	entries := make([]string, len(m.keys))
+
+	for i, key := range m.keys {
+		entries[i] = fmt.Sprintf("%s: %s", stringifyNested(key), stringifyNested(m.values[key]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+// Checks if a runtime value can be used as map key.
+func validateKey(key any, position lexer.Position) error {
+	switch key.(type) {
+	case string, float64, bool:
+		return nil
+	default:
+		return createASTErrorAt(fmt.Sprintf("value of type %s can't be used as map key", typeName(key)), position)
+	}
+}
@@ -0,0 +1,121 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encodes a syntax tree, or any of its nodes, into a Graphviz DOT graph.
+//
+// Each node of the tree is drawn as a box labeled with its type, its operator, name or
+// value when it has any, and its span. Edges go from each node to its children, in source order.
+//
+// digraph AST { n0 [label="Binary\n+\n1:1-1:6"]; n0 -> n1; ... }
+func EncodeDOT(node Node) string {
+	w := &dotWalker{}
+	w.out.WriteString("digraph AST {\n")
+	w.out.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+
+	Walk(w, node)
+
+	w.out.WriteString("}\n")
+
+	return w.out.String()
+}
+
+// Walker which writes each visited node, and the edge from its parent.
+type dotWalker struct {
+	out     strings.Builder
+	parents []int // ids of the nodes whose children are being visited
+	next    int
+}
+
+func (w *dotWalker) Visit(node Node) Walker {
+	if node == nil {
+		w.parents = w.parents[:len(w.parents)-1]
+		return nil
+	}
+
+	id := w.next
+	w.next++
+
+	label := []string{strings.TrimPrefix(fmt.Sprintf("%T", node), "ast.")}
+
+	if detail := dotDetail(node); detail != "" {
+		label = append(label, detail)
+	}
+
+	label = append(label, node.Span().String())
+	fmt.Fprintf(&w.out, "\tn%d [label=\"%s\"];\n", id, strings.Join(label, `\n`))
+
+	if len(w.parents) > 0 {
+		fmt.Fprintf(&w.out, "\tn%d -> n%d;\n", w.parents[len(w.parents)-1], id)
+	}
+
+	w.parents = append(w.parents, id)
+
+	return w
+}
+
+// Describes what tells a node apart from others of the same type: its operator, name or value.
+// Returned text is escaped to be placed within a quoted DOT label.
+func dotDetail(node Node) string {
+	var detail string
+
+	switch n := node.(type) {
+	case Binary:
+		detail = n.operator.Lexeme
+	case Logical:
+		detail = n.operator.Lexeme
+	case Unary:
+		detail = n.operator.Lexeme
+	case Literal:
+		detail = typeName(n.value)
+
+		if value, ok := n.value.(string); ok {
+			detail += fmt.Sprintf(" %q", value)
+		} else if n.value != nil {
+			detail += " " + n.String()
+		}
+	case Variable:
+		detail = n.name
+	case Assign:
+		detail = n.name
+	case Get:
+		detail = optionalMark(n.optional) + n.name
+	case Call:
+		detail = optionalMark(n.optional)
+	case NamedArgument:
+		detail = n.name
+	case Index:
+		detail = optionalMark(n.optional)
+	case Slice:
+		detail = optionalMark(n.optional)
+	case Set:
+		detail = n.name
+	case Super:
+		detail = n.method
+	case WhileStmt:
+		detail = n.label
+	case ForStmt:
+		detail = n.label
+	case BreakStmt:
+		detail = n.label
+	case ContinueStmt:
+		detail = n.label
+	case FunctionStmt:
+		detail = n.name
+	case ClassStmt:
+		detail = n.name
+	case IdentifierPattern:
+		detail = n.name
+	case RestPattern:
+		detail = n.name
+	case MapPatternEntry:
+		detail = n.key
+	case InstancePattern:
+		detail = n.class
+	}
+
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(detail)
+}
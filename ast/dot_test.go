@@ -0,0 +1,36 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDOT(t *testing.T) {
+	t.Run("should draw each node with an edge from its parent", func(t *testing.T) {
+		program, _ := Parse(`print -a * "b";`)
+		expected := `digraph AST {
+	node [shape=box, fontname="monospace"];
+	n0 [label="Program\n1:1-1:16"];
+	n1 [label="PrintStmt\n1:1-1:16"];
+	n0 -> n1;
+	n2 [label="Binary\n*\n1:7-1:15"];
+	n1 -> n2;
+	n3 [label="Unary\n-\n1:7-1:9"];
+	n2 -> n3;
+	n4 [label="Variable\na\n1:8-1:9"];
+	n3 -> n4;
+	n5 [label="Literal\nstring \"b\"\n1:12-1:15"];
+	n2 -> n5;
+}
+`
+
+		assert.Equal(t, expected, EncodeDOT(program))
+	})
+
+	t.Run("should draw map pattern entries by their keys", func(t *testing.T) {
+		program, _ := Parse(`var {name: n} = user;`)
+
+		assert.Contains(t, EncodeDOT(program), `[label="MapPatternEntry\nname\n1:6-1:13"];`)
+	})
+}
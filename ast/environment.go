@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Environment stores the values bound to variable names within a scope.
+//
+// Environments are chained: when a name is not found at the current scope, lookup
+// continues at the enclosing one until the global scope is reached.
+type Environment struct {
+	values    map[string]any // variables declared at this scope
+	enclosing *Environment   // parent scope, nil for global scope
+	out       io.Writer      // destination for print statements
+}
+
+// Creates a global environment whose print statements write into the given output.
+// If output is nil, standard output is used. Native functions are defined at this scope.
+func NewGlobalEnvironment(out io.Writer) *Environment {
+	if out == nil {
+		out = os.Stdout
+	}
+
+	env := &Environment{
+		values:    make(map[string]any),
+		enclosing: nil,
+		out:       out,
+	}
+
+	for _, native := range builtins() {
+		env.Define(native.name, native)
+	}
+
+	return env
+}
+
+// Creates a nested environment from the given enclosing one.
+// If enclosing is nil, a global environment writing to standard output is created.
+func NewEnvironment(enclosing *Environment) *Environment {
+	if enclosing == nil {
+		return NewGlobalEnvironment(nil)
+	}
+
+	return &Environment{
+		values:    make(map[string]any),
+		enclosing: enclosing,
+		out:       enclosing.out,
+	}
+}
+
+// Binds the value to the given name at current scope. Redefining a name overrides its value.
+func (e *Environment) Define(name string, value any) {
+	e.values[name] = value
+}
+
+// Looks up the value bound to the given name through the scopes chain.
+func (e *Environment) Get(name string) (any, error) {
+	if value, ok := e.values[name]; ok {
+		return value, nil
+	}
+
+	if e.enclosing != nil {
+		return e.enclosing.Get(name)
+	}
+
+	return nil, createASTError(fmt.Sprintf("undefined variable '%s'", name))
+}
+
+// Updates the value bound to the given name at the nearest scope which declares it.
+// Assigning to an undeclared name is an error.
+func (e *Environment) Assign(name string, value any) error {
+	if _, ok := e.values[name]; ok {
+		e.values[name] = value
+		return nil
+	}
+
+	if e.enclosing != nil {
+		return e.enclosing.Assign(name, value)
+	}
+
+	return createASTError(fmt.Sprintf("cannot assign to undeclared variable '%s'", name))
+}
@@ -2,10 +2,87 @@ package ast
 
 import (
 	"fmt"
+
+	"github.com/alfredoprograma/gox/lexer"
 )
 
 const AST_PREFIX = "[AST]"
 
+// Exposes when, during execution, a program can't be computed.
+//
+// Errors raised by helpers which don't know where they are used (environments, instances, natives)
+// have no position; the node which calls them locates the error before returning it.
+type runtimeError struct {
+	msg      string
+	position lexer.Position // zero until the error is located
+}
+
 func createASTError(msg string) error {
-	return fmt.Errorf("%s: %s", AST_PREFIX, msg)
+	return runtimeError{msg, lexer.Position{}}
+}
+
+// Creates a runtime error located at the given source position.
+func createASTErrorAt(msg string, position lexer.Position) error {
+	return runtimeError{msg, position}
+}
+
+// Sets the given position to a runtime error which is not located yet. Any other error is returned as is.
+func locate(err error, position lexer.Position) error {
+	if e, ok := err.(runtimeError); ok && e.position == (lexer.Position{}) {
+		e.position = position
+		return e
+	}
+
+	return err
+}
+
+func (e runtimeError) Error() string {
+	if e.position == (lexer.Position{}) {
+		return fmt.Sprintf("%s: %s", AST_PREFIX, e.msg)
+	}
+
+	return fmt.Sprintf("%s: %s at %s", AST_PREFIX, e.msg, e.position)
+}
+
+// Exposes when, during parsing process, tokens stream doesn't follow Gox grammar.
+type syntaxError struct {
+	msg      string
+	position lexer.Position // start of the offending token
+}
+
+func newSyntaxError(msg string, position lexer.Position) syntaxError {
+	return syntaxError{msg, position}
+}
+
+func (e syntaxError) Error() string {
+	return fmt.Sprintf("%s: %s at %s", AST_PREFIX, e.msg, e.position)
+}
+
+// Exposes when, during parsing process, tokens stream follows Gox grammar but it is likely a mistake.
+// Unlike syntax errors, warnings don't prevent the program to be executed.
+type warning struct {
+	msg      string
+	position lexer.Position
+}
+
+func newWarning(msg string, position lexer.Position) warning {
+	return warning{msg, position}
+}
+
+func (w warning) Error() string {
+	return fmt.Sprintf("%s: warning: %s at %s", AST_PREFIX, w.msg, w.position)
+}
+
+// Exposes when, during destructuring, a value doesn't have the shape described by a pattern.
+type patternMismatchError struct {
+	msg      string
+	position lexer.Position
+}
+
+func newPatternMismatchError(msg string, position lexer.Position) patternMismatchError {
+	return patternMismatchError{msg, position}
+}
+
+func (e patternMismatchError) Error() string {
+	return fmt.Sprintf("%s: %s at %s", AST_PREFIX, e.msg, e.position)
 }
@@ -3,114 +3,1242 @@ package ast
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/alfredoprograma/gox/lexer"
 )
 
 // An expression can generate a direct result from it.
 type Expr interface {
-	String() string // Exposes stringified version of the expression.
-	Compute() (any, error)
+	String() string                        // Exposes stringified version of the expression.
+	Span() lexer.Span                      // Exposes the range of source the expression was parsed from.
+	Compute(env *Environment) (any, error) // Evaluates the expression within the given environment.
 }
 
 // An expression composed by two nested expressions and an operator.
 type Binary struct {
 	left     Expr
-	operator lexer.TokenKind
+	operator lexer.Token
 	right    Expr
 }
 
-func NewBinary(left Expr, operator lexer.TokenKind, right Expr) Expr {
+func NewBinary(left Expr, operator lexer.Token, right Expr) Expr {
 	return Binary{left, operator, right}
 }
 
+func (b Binary) Left() Expr {
+	return b.left
+}
+
+func (b Binary) Operator() lexer.Token {
+	return b.operator
+}
+
+func (b Binary) Right() Expr {
+	return b.right
+}
+
 func (b Binary) String() string {
-	return fmt.Sprintf("(%s %s %s)", b.left.String(), lexer.TokenKindToLexemeMap[b.operator], b.right.String())
+	return fmt.Sprintf("(%s %s %s)", b.left.String(), b.operator.Lexeme, b.right.String())
+}
+
+func (b Binary) Span() lexer.Span {
+	return lexer.Join(b.left.Span(), b.right.Span())
+}
+
+func (b Binary) Compute(env *Environment) (any, error) {
+	left, err := b.left.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := b.right.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.operator.Kind {
+	case lexer.DoubleEqual:
+		return isEqual(left, right), nil
+	case lexer.BangEqual:
+		return !isEqual(left, right), nil
+	}
+
+	switch leftValue := left.(type) {
+	case float64:
+		switch rightValue := right.(type) {
+		case float64:
+			value, err := computeNumberBinaryOperation(leftValue, b.operator.Kind, rightValue)
+			return value, locate(err, b.operator.Span().Start)
+		default:
+			break
+		}
+	case string:
+		switch rightValue := right.(type) {
+		case string:
+			value, err := computeStringBinaryOperation(leftValue, b.operator.Kind, rightValue)
+			return value, locate(err, b.operator.Span().Start)
+		default:
+			break
+		}
+	default:
+		break
+	}
+
+	return nil, createASTErrorAt(fmt.Sprintf("unrecognized value types %s and %s for binary operation", typeName(left), typeName(right)), b.operator.Span().Start)
+}
+
+// An expression composed by two nested expressions and a logical operator (and, or, ??).
+//
+// Right operand is only computed when left one doesn't determine the result by itself.
+// It results into the operand which determined the result, not into a boolean.
+type Logical struct {
+	left     Expr
+	operator lexer.Token
+	right    Expr
+}
+
+func NewLogical(left Expr, operator lexer.Token, right Expr) Expr {
+	return Logical{left, operator, right}
+}
+
+func (l Logical) Left() Expr {
+	return l.left
+}
+
+func (l Logical) Operator() lexer.Token {
+	return l.operator
+}
+
+func (l Logical) Right() Expr {
+	return l.right
+}
+
+func (l Logical) String() string {
+	return fmt.Sprintf("(%s %s %s)", l.left.String(), l.operator.Lexeme, l.right.String())
+}
+
+func (l Logical) Span() lexer.Span {
+	return lexer.Join(l.left.Span(), l.right.Span())
+}
+
+func (l Logical) Compute(env *Environment) (any, error) {
+	left, err := l.left.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l.operator.Kind == lexer.Or && isTruthy(left) {
+		return left, nil
+	}
+
+	if l.operator.Kind == lexer.And && !isTruthy(left) {
+		return left, nil
+	}
+
+	// Unlike or, null coalescing only falls back on null; any other falsy value is kept.
+	if l.operator.Kind == lexer.DoubleQuestion && left != nil {
+		return left, nil
+	}
+
+	return l.right.Compute(env)
+}
+
+// An expression which computes only one of its branches, depending on the truthiness of its condition.
+type Conditional struct {
+	condition  Expr
+	thenBranch Expr
+	elseBranch Expr
+}
+
+func NewConditional(condition Expr, thenBranch Expr, elseBranch Expr) Expr {
+	return Conditional{condition, thenBranch, elseBranch}
+}
+
+func (c Conditional) Condition() Expr {
+	return c.condition
+}
+
+func (c Conditional) ThenBranch() Expr {
+	return c.thenBranch
+}
+
+func (c Conditional) ElseBranch() Expr {
+	return c.elseBranch
+}
+
+func (c Conditional) String() string {
+	return fmt.Sprintf("(%s ? %s : %s)", c.condition.String(), c.thenBranch.String(), c.elseBranch.String())
+}
+
+func (c Conditional) Span() lexer.Span {
+	return lexer.Join(c.condition.Span(), c.elseBranch.Span())
+}
+
+func (c Conditional) Compute(env *Environment) (any, error) {
+	condition, err := c.condition.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isTruthy(condition) {
+		return c.thenBranch.Compute(env)
+	}
+
+	return c.elseBranch.Compute(env)
+}
+
+// An expression composed by an expression and an operator.
+type Unary struct {
+	operator lexer.Token
+	right    Expr
+}
+
+func NewUnary(operator lexer.Token, right Expr) Expr {
+	return Unary{operator, right}
+}
+
+func (u Unary) Operator() lexer.Token {
+	return u.operator
+}
+
+func (u Unary) Right() Expr {
+	return u.right
+}
+
+func (u Unary) String() string {
+	return fmt.Sprintf("(%s%s)", u.operator.Lexeme, u.right.String())
+}
+
+func (u Unary) Span() lexer.Span {
+	return lexer.Join(u.operator.Span(), u.right.Span())
+}
+
+func (u Unary) Compute(env *Environment) (any, error) {
+	right, err := u.right.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if u.operator.Kind == lexer.Bang {
+		return !isTruthy(right), nil
+	}
+
+	switch value := right.(type) {
+	case float64:
+		result, err := computeNumberUnaryOperation(u.operator.Kind, value)
+		return result, locate(err, u.operator.Span().Start)
+	default:
+		return nil, createASTErrorAt(fmt.Sprintf("unrecognized value type %s for unary operation", typeName(value)), u.operator.Span().Start)
+	}
+
+}
+
+// Expressions which access into the value of another expression (property reads, calls, indexes
+// and slices) are the links of a chain. An optional link whose object is null short-circuits
+// the rest of the chain, so the whole chain results into null.
+//
+// a?.b.c is null when a is null, instead of failing to read c from null.
+type chainLink interface {
+	Expr
+	computeLink(env *Environment) (value any, shortCircuited bool, err error)
+}
+
+// Computes the object accessed by a link of a chain. It reports when the chain short-circuits,
+// either within the object itself or because the link is optional and the object is null.
+func computeChainObject(object Expr, optional bool, env *Environment) (any, bool, error) {
+	var value any
+	var shortCircuited bool
+	var err error
+
+	if link, ok := object.(chainLink); ok {
+		value, shortCircuited, err = link.computeLink(env)
+	} else {
+		value, err = object.Compute(env)
+	}
+
+	if err != nil || shortCircuited {
+		return nil, shortCircuited, err
+	}
+
+	return value, optional && value == nil, nil
+}
+
+// An expression which invokes a callable value with a list of arguments.
+// It keeps the span of the closing paren to locate runtime errors.
+//
+// Optional calls (f?.()) result into null when the callee is null.
+type Call struct {
+	callee   Expr
+	args     []Expr
+	optional bool
+	paren    lexer.Span
+}
+
+func NewCall(callee Expr, args []Expr, optional bool, paren lexer.Span) Expr {
+	return Call{callee, args, optional, paren}
+}
+
+func (c Call) Callee() Expr {
+	return c.callee
+}
+
+func (c Call) Args() []Expr {
+	return c.args
+}
+
+func (c Call) Optional() bool {
+	return c.optional
+}
+
+func (c Call) Paren() lexer.Span {
+	return c.paren
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s%s(%s)", c.callee.String(), optionalMark(c.optional), joinExprs(c.args))
+}
+
+func (c Call) Span() lexer.Span {
+	return lexer.Join(c.callee.Span(), c.paren)
+}
+
+func (c Call) Compute(env *Environment) (any, error) {
+	value, _, err := c.computeLink(env)
+	return value, err
+}
+
+func (c Call) computeLink(env *Environment) (any, bool, error) {
+	callee, shortCircuited, err := computeChainObject(c.callee, c.optional, env)
+
+	if err != nil || shortCircuited {
+		return nil, shortCircuited, err
+	}
+
+	positional, named, err := c.computeArguments(env)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	callable, ok := callee.(Callable)
+
+	if !ok {
+		return nil, false, createASTErrorAt(fmt.Sprintf("value of type %s is not callable", typeName(callee)), c.paren.Start)
+	}
+
+	args, err := resolveArguments(callable, positional, named)
+
+	if err != nil {
+		return nil, false, locate(err, c.paren.Start)
+	}
+
+	value, err := callable.Call(args)
+
+	return value, false, locate(err, c.paren.Start)
+}
+
+// Computes positional arguments, expanding spread ones, followed by named arguments.
+func (c Call) computeArguments(env *Environment) ([]any, []namedValue, error) {
+	positional := make([]Expr, 0, len(c.args))
+	namedArgs := make([]NamedArgument, 0)
+
+	for _, arg := range c.args {
+		if named, ok := arg.(NamedArgument); ok {
+			namedArgs = append(namedArgs, named)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+
+	args, err := computeSpreading(positional, env)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	named := make([]namedValue, len(namedArgs))
+
+	for i, arg := range namedArgs {
+		value, err := arg.value.Compute(env)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		named[i] = namedValue{arg.name, value, arg.nameSpan.Start}
+	}
+
+	return args, named, nil
+}
+
+// An argument passed by the name of its parameter. Only allowed within call arguments,
+// after the positional ones.
+//
+// connect("db", tls: false)
+type NamedArgument struct {
+	name     string
+	nameSpan lexer.Span
+	value    Expr
+}
+
+func NewNamedArgument(name string, nameSpan lexer.Span, value Expr) Expr {
+	return NamedArgument{name, nameSpan, value}
+}
+
+func (n NamedArgument) Name() string {
+	return n.name
+}
+
+func (n NamedArgument) NameSpan() lexer.Span {
+	return n.nameSpan
+}
+
+func (n NamedArgument) Value() Expr {
+	return n.value
+}
+
+func (n NamedArgument) String() string {
+	return fmt.Sprintf("%s: %s", n.name, n.value.String())
+}
+
+func (n NamedArgument) Span() lexer.Span {
+	return lexer.Join(n.nameSpan, n.value.Span())
+}
+
+func (n NamedArgument) Compute(env *Environment) (any, error) {
+	return nil, createASTErrorAt("named argument is only allowed within call arguments", n.nameSpan.Start)
+}
+
+// An expression which destructures a value into already declared variables, following its target pattern.
+// It results into the assigned value.
+type DestructuringAssign struct {
+	target Pattern
+	value  Expr
+}
+
+func NewDestructuringAssign(target Pattern, value Expr) Expr {
+	return DestructuringAssign{target, value}
+}
+
+func (d DestructuringAssign) Target() Pattern {
+	return d.target
+}
+
+func (d DestructuringAssign) Value() Expr {
+	return d.value
+}
+
+func (d DestructuringAssign) String() string {
+	return fmt.Sprintf("(%s = %s)", d.target.String(), d.value.String())
+}
+
+func (d DestructuringAssign) Span() lexer.Span {
+	return lexer.Join(d.target.Span(), d.value.Span())
+}
+
+func (d DestructuringAssign) Compute(env *Environment) (any, error) {
+	value, err := d.value.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.target.bind(env, value, assignInto(env)); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// A single arm of a match expression. Guard is optional; when it is not nil, arm is only
+// selected if guard is truthy after binding the pattern.
+type MatchArm struct {
+	pattern Pattern
+	guard   Expr
+	body    Expr
+}
+
+func (a MatchArm) Pattern() Pattern {
+	return a.pattern
+}
+
+func (a MatchArm) Guard() Expr {
+	return a.guard
+}
+
+func (a MatchArm) Body() Expr {
+	return a.body
+}
+
+func (a MatchArm) String() string {
+	if a.guard == nil {
+		return fmt.Sprintf("%s => %s", a.pattern.String(), a.body.String())
+	}
+
+	return fmt.Sprintf("%s if %s => %s", a.pattern.String(), a.guard.String(), a.body.String())
+}
+
+func (a MatchArm) Span() lexer.Span {
+	return lexer.Join(a.pattern.Span(), a.body.Span())
+}
+
+// An expression which compares a value against the patterns of its arms, in order, and
+// results into the body of the first arm which matches. Names bound by the pattern are
+// only visible within its guard and body.
+//
+// Failing to match every arm is an error.
+type Match struct {
+	subject Expr
+	arms    []MatchArm
+	span    lexer.Span
+}
+
+func NewMatch(subject Expr, arms []MatchArm, span lexer.Span) Expr {
+	return Match{subject, arms, span}
+}
+
+func (m Match) Subject() Expr {
+	return m.subject
+}
+
+func (m Match) Arms() []MatchArm {
+	return m.arms
+}
+
+func (m Match) String() string {
+	arms := make([]string, len(m.arms))
+
+	for i, arm := range m.arms {
+		arms[i] = arm.String()
+	}
+
+	return fmt.Sprintf("match %s { %s }", m.subject.String(), strings.Join(arms, ", "))
+}
+
+func (m Match) Span() lexer.Span {
+	return m.span
+}
+
+func (m Match) Compute(env *Environment) (any, error) {
+	subject, err := m.subject.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arm := range m.arms {
+		armEnv := NewEnvironment(env)
+		err := arm.pattern.bind(armEnv, subject, declareInto(armEnv))
+
+		if _, isMismatch := err.(patternMismatchError); isMismatch {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if arm.guard != nil {
+			guard, err := arm.guard.Compute(armEnv)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return arm.body.Compute(armEnv)
+	}
+
+	return nil, createASTErrorAt(fmt.Sprintf("no match arm for value %s", stringifyNested(subject)), m.span.Start)
+}
+
+// An expression which reads a property from an instance.
+//
+// Optional reads (a?.b) result into null when the object is null.
+type Get struct {
+	object   Expr
+	name     string
+	optional bool
+	nameSpan lexer.Span
+}
+
+func NewGet(object Expr, name string, optional bool, nameSpan lexer.Span) Expr {
+	return Get{object, name, optional, nameSpan}
+}
+
+func (g Get) Object() Expr {
+	return g.object
+}
+
+func (g Get) Name() string {
+	return g.name
+}
+
+func (g Get) Optional() bool {
+	return g.optional
+}
+
+func (g Get) NameSpan() lexer.Span {
+	return g.nameSpan
+}
+
+func (g Get) String() string {
+	if g.optional {
+		return fmt.Sprintf("%s?.%s", g.object.String(), g.name)
+	}
+
+	return fmt.Sprintf("%s.%s", g.object.String(), g.name)
+}
+
+func (g Get) Span() lexer.Span {
+	return lexer.Join(g.object.Span(), g.nameSpan)
+}
+
+func (g Get) Compute(env *Environment) (any, error) {
+	value, _, err := g.computeLink(env)
+	return value, err
+}
+
+func (g Get) computeLink(env *Environment) (any, bool, error) {
+	object, shortCircuited, err := computeChainObject(g.object, g.optional, env)
+
+	if err != nil || shortCircuited {
+		return nil, shortCircuited, err
+	}
+
+	instance, ok := object.(*Instance)
+
+	if !ok {
+		return nil, false, createASTErrorAt(fmt.Sprintf("can't read property '%s' from value of type %s", g.name, typeName(object)), g.nameSpan.Start)
+	}
+
+	value, err := instance.Get(g.name)
+
+	return value, false, locate(err, g.nameSpan.Start)
+}
+
+// An expression which writes a property into an instance. It results into the assigned value.
+type Set struct {
+	object   Expr
+	name     string
+	value    Expr
+	nameSpan lexer.Span
+}
+
+func NewSet(object Expr, name string, value Expr, nameSpan lexer.Span) Expr {
+	return Set{object, name, value, nameSpan}
+}
+
+func (s Set) Object() Expr {
+	return s.object
+}
+
+func (s Set) Name() string {
+	return s.name
+}
+
+func (s Set) Value() Expr {
+	return s.value
+}
+
+func (s Set) NameSpan() lexer.Span {
+	return s.nameSpan
+}
+
+func (s Set) String() string {
+	return fmt.Sprintf("(%s.%s = %s)", s.object.String(), s.name, s.value.String())
+}
+
+func (s Set) Span() lexer.Span {
+	return lexer.Join(s.object.Span(), s.value.Span())
+}
+
+func (s Set) Compute(env *Environment) (any, error) {
+	object, err := s.object.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := object.(*Instance)
+
+	if !ok {
+		return nil, createASTErrorAt(fmt.Sprintf("can't write property '%s' into value of type %s", s.name, typeName(object)), s.nameSpan.Start)
+	}
+
+	value, err := s.value.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instance.Set(s.name, value)
+	return value, nil
+}
+
+// An expression which references the instance a method is bound to.
+type This struct {
+	span lexer.Span
+}
+
+func NewThis(span lexer.Span) Expr {
+	return This{span}
+}
+
+func (t This) String() string {
+	return THIS_NAME
+}
+
+func (t This) Span() lexer.Span {
+	return t.span
+}
+
+func (t This) Compute(env *Environment) (any, error) {
+	value, err := env.Get(THIS_NAME)
+
+	return value, locate(err, t.span.Start)
+}
+
+// An expression which references a superclass method, bound to the current instance.
+type Super struct {
+	method string
+	span   lexer.Span
+}
+
+func NewSuper(method string, span lexer.Span) Expr {
+	return Super{method, span}
+}
+
+func (s Super) Method() string {
+	return s.method
+}
+
+func (s Super) String() string {
+	return fmt.Sprintf("%s.%s", SUPER_NAME, s.method)
+}
+
+func (s Super) Span() lexer.Span {
+	return s.span
+}
+
+func (s Super) Compute(env *Environment) (any, error) {
+	superclass, err := env.Get(SUPER_NAME)
+
+	if err != nil {
+		return nil, locate(err, s.span.Start)
+	}
+
+	instance, err := env.Get(THIS_NAME)
+
+	if err != nil {
+		return nil, locate(err, s.span.Start)
+	}
+
+	class := superclass.(*Class)
+	method, ok := class.findMethod(s.method)
+
+	if !ok {
+		return nil, createASTErrorAt(fmt.Sprintf("undefined method '%s' on superclass %s", s.method, class.String()), s.span.Start)
+	}
+
+	return method.bind(instance.(*Instance)), nil
+}
+
+// An expression which builds a new list from its computed elements.
+type ListLiteral struct {
+	elements []Expr
+	span     lexer.Span
+}
+
+func NewListLiteral(elements []Expr, span lexer.Span) Expr {
+	return ListLiteral{elements, span}
 }
 
-func (b Binary) Compute() (any, error) {
-	left, err := b.left.Compute()
+func (l ListLiteral) Elements() []Expr {
+	return l.elements
+}
+
+func (l ListLiteral) String() string {
+	return fmt.Sprintf("[%s]", joinExprs(l.elements))
+}
+
+func (l ListLiteral) Span() lexer.Span {
+	return l.span
+}
+
+func (l ListLiteral) Compute(env *Environment) (any, error) {
+	elements, err := computeSpreading(l.elements, env)
 
 	if err != nil {
 		return nil, err
 	}
 
-	right, err := b.right.Compute()
+	return newList(elements), nil
+}
+
+// An expression which expands the elements of a list in place. Only allowed
+// within call arguments and list literals, which compute it through computeSpreading.
+//
+// f(...args)
+type Spread struct {
+	expr Expr
+	span lexer.Span
+}
+
+func NewSpread(expr Expr, span lexer.Span) Expr {
+	return Spread{expr, span}
+}
+
+func (s Spread) Expr() Expr {
+	return s.expr
+}
+
+func (s Spread) String() string {
+	return "..." + s.expr.String()
+}
+
+func (s Spread) Span() lexer.Span {
+	return s.span
+}
+
+func (s Spread) Compute(env *Environment) (any, error) {
+	return nil, createASTErrorAt("spread is only allowed within call arguments and list literals", s.span.Start)
+}
+
+// Computes the elements to expand. Only lists can be spread.
+func (s Spread) computeElements(env *Environment) ([]any, error) {
+	value, err := s.expr.Compute(env)
 
 	if err != nil {
 		return nil, err
 	}
 
-	switch leftValue := left.(type) {
-	case float64:
-		switch rightValue := right.(type) {
-		case float64:
-			return computeNumberBinaryOperation(leftValue, b.operator, rightValue)
-		default:
-			break
+	list, ok := value.(*List)
+
+	if !ok {
+		return nil, createASTErrorAt(fmt.Sprintf("can't spread value of type %s, it is not iterable", typeName(value)), s.span.Start)
+	}
+
+	return list.elements, nil
+}
+
+// Computes each expression in order, expanding the elements of spread ones in place.
+func computeSpreading(exprs []Expr, env *Environment) ([]any, error) {
+	values := make([]any, 0, len(exprs))
+
+	for _, expr := range exprs {
+		if spread, ok := expr.(Spread); ok {
+			elements, err := spread.computeElements(env)
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, elements...)
+			continue
+		}
+
+		value, err := expr.Compute(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// An expression which builds a new map from its computed entries, keeping their order.
+type MapLiteral struct {
+	keys   []Expr
+	values []Expr
+	span   lexer.Span
+}
+
+func NewMapLiteral(keys []Expr, values []Expr, span lexer.Span) Expr {
+	return MapLiteral{keys, values, span}
+}
+
+func (m MapLiteral) Keys() []Expr {
+	return m.keys
+}
+
+func (m MapLiteral) Values() []Expr {
+	return m.values
+}
+
+func (m MapLiteral) String() string {
+	entries := make([]string, len(m.keys))
+
+	for i := range m.keys {
+		entries[i] = fmt.Sprintf("%s: %s", m.keys[i].String(), m.values[i].String())
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+func (m MapLiteral) Span() lexer.Span {
+	return m.span
+}
+
+func (m MapLiteral) Compute(env *Environment) (any, error) {
+	result := newMap()
+
+	for i := range m.keys {
+		key, err := m.keys[i].Compute(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := m.values[i].Compute(env)
+
+		if err != nil {
+			return nil, err
 		}
+
+		if err := result.set(key, value, m.keys[i].Span().Start); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// An expression which reads an element from a collection by its index.
+// It keeps the span of the brackets to locate runtime errors.
+//
+// Optional indexes (xs?.[i]) result into null when the collection is null.
+type Index struct {
+	object   Expr
+	index    Expr
+	optional bool
+	brackets lexer.Span
+}
+
+func NewIndex(object Expr, index Expr, optional bool, brackets lexer.Span) Expr {
+	return Index{object, index, optional, brackets}
+}
+
+func (i Index) Object() Expr {
+	return i.object
+}
+
+func (i Index) Index() Expr {
+	return i.index
+}
+
+func (i Index) Optional() bool {
+	return i.optional
+}
+
+func (i Index) Brackets() lexer.Span {
+	return i.brackets
+}
+
+func (i Index) String() string {
+	return fmt.Sprintf("%s%s[%s]", i.object.String(), optionalMark(i.optional), i.index.String())
+}
+
+func (i Index) Span() lexer.Span {
+	return lexer.Join(i.object.Span(), i.brackets)
+}
+
+func (i Index) Compute(env *Environment) (any, error) {
+	value, _, err := i.computeLink(env)
+	return value, err
+}
+
+func (i Index) computeLink(env *Environment) (any, bool, error) {
+	object, shortCircuited, err := computeChainObject(i.object, i.optional, env)
+
+	if err != nil || shortCircuited {
+		return nil, shortCircuited, err
+	}
+
+	index, err := i.index.Compute(env)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value any
+
+	switch collection := object.(type) {
+	case *List:
+		value, err = collection.get(index, i.brackets.Start)
+	case *Map:
+		value, err = collection.get(index, i.brackets.Start)
 	default:
-		break
+		err = createASTErrorAt(fmt.Sprintf("can't index value of type %s", typeName(object)), i.brackets.Start)
 	}
 
-	return nil, createASTError(fmt.Sprintf("unrecognized value types %t and %t for binary operation", left, right))
+	return value, false, err
 }
 
-// An expression composed by an expression and an operator.
-type Unary struct {
-	operator lexer.TokenKind
-	right    Expr
+// An expression which writes an element into a collection by its index. It results into the assigned value.
+type IndexSet struct {
+	object   Expr
+	index    Expr
+	value    Expr
+	brackets lexer.Span
 }
 
-func NewUnary(operator lexer.TokenKind, right Expr) Expr {
-	return Unary{operator, right}
+func NewIndexSet(object Expr, index Expr, value Expr, brackets lexer.Span) Expr {
+	return IndexSet{object, index, value, brackets}
 }
 
-func (u Unary) String() string {
-	return fmt.Sprintf("(%s%s)", lexer.TokenKindToLexemeMap[u.operator], u.right.String())
+func (i IndexSet) Object() Expr {
+	return i.object
+}
+
+func (i IndexSet) Index() Expr {
+	return i.index
+}
+
+func (i IndexSet) Value() Expr {
+	return i.value
+}
+
+func (i IndexSet) Brackets() lexer.Span {
+	return i.brackets
+}
+
+func (i IndexSet) String() string {
+	return fmt.Sprintf("(%s[%s] = %s)", i.object.String(), i.index.String(), i.value.String())
+}
+
+func (i IndexSet) Span() lexer.Span {
+	return lexer.Join(i.object.Span(), i.value.Span())
 }
 
-func (u Unary) Compute() (any, error) {
-	right, err := u.right.Compute()
+func (i IndexSet) Compute(env *Environment) (any, error) {
+	object, err := i.object.Compute(env)
 
 	if err != nil {
 		return nil, err
 	}
 
-	switch value := right.(type) {
-	case float64:
-		return computeNumberUnaryOperation(u.operator, value)
+	index, err := i.index.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := i.value.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch collection := object.(type) {
+	case *List:
+		err = collection.set(index, value, i.brackets.Start)
+	case *Map:
+		err = collection.set(index, value, i.brackets.Start)
 	default:
-		return nil, createASTError(fmt.Sprintf("unrecognized value type %t for unary operation", value))
+		err = createASTErrorAt(fmt.Sprintf("can't index value of type %s", typeName(object)), i.brackets.Start)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// An expression which copies a range of elements from a list into a new list.
+// Both bounds are optional; when they are nil, slice starts at the beginning or ends at the end of the list.
+//
+// Optional slices (xs?.[1:]) result into null when the list is null.
+type Slice struct {
+	object   Expr
+	start    Expr
+	end      Expr
+	optional bool
+	brackets lexer.Span
+}
+
+func NewSlice(object Expr, start Expr, end Expr, optional bool, brackets lexer.Span) Expr {
+	return Slice{object, start, end, optional, brackets}
+}
+
+func (s Slice) Object() Expr {
+	return s.object
+}
+
+func (s Slice) Start() Expr {
+	return s.start
+}
+
+func (s Slice) End() Expr {
+	return s.end
+}
+
+func (s Slice) Optional() bool {
+	return s.optional
+}
+
+func (s Slice) String() string {
+	start := ""
+	end := ""
+
+	if s.start != nil {
+		start = s.start.String()
+	}
+
+	if s.end != nil {
+		end = s.end.String()
+	}
+
+	return fmt.Sprintf("%s%s[%s:%s]", s.object.String(), optionalMark(s.optional), start, end)
+}
+
+func (s Slice) Brackets() lexer.Span {
+	return s.brackets
+}
+
+func (s Slice) Span() lexer.Span {
+	return lexer.Join(s.object.Span(), s.brackets)
+}
+
+func (s Slice) Compute(env *Environment) (any, error) {
+	value, _, err := s.computeLink(env)
+	return value, err
+}
+
+func (s Slice) computeLink(env *Environment) (any, bool, error) {
+	object, shortCircuited, err := computeChainObject(s.object, s.optional, env)
+
+	if err != nil || shortCircuited {
+		return nil, shortCircuited, err
+	}
+
+	bounds := make([]any, 2)
+
+	for i, bound := range []Expr{s.start, s.end} {
+		if bound == nil {
+			continue
+		}
+
+		value, err := bound.Compute(env)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		bounds[i] = value
+	}
+
+	list, ok := object.(*List)
+
+	if !ok {
+		return nil, false, createASTErrorAt(fmt.Sprintf("can't slice value of type %s", typeName(object)), s.brackets.Start)
+	}
+
+	value, err := list.slice(bounds[0], bounds[1], s.brackets.Start)
+
+	return value, false, err
+}
+
+// An anonymous function expression. It results into a function value closing over
+// the environment where it is computed.
+//
+// Arrow functions with an expression body hold a single return statement.
+type Lambda struct {
+	params []Pattern
+	body   []Stmt
+	arrow  bool // declared with arrow syntax
+	span   lexer.Span
+}
+
+func NewLambda(params []Pattern, body []Stmt, arrow bool, span lexer.Span) Expr {
+	return Lambda{params, body, arrow, span}
+}
+
+func (l Lambda) Params() []Pattern {
+	return l.params
+}
+
+func (l Lambda) Body() []Stmt {
+	return l.body
+}
+
+func (l Lambda) Arrow() bool {
+	return l.arrow
+}
+
+func (l Lambda) String() string {
+	params := joinPatterns(l.params)
+
+	if !l.arrow {
+		return fmt.Sprintf("function (%s) %s", params, NewBlockStmt(l.body, l.span).String())
+	}
+
+	if len(l.body) == 1 {
+		if ret, ok := l.body[0].(ReturnStmt); ok && ret.value != nil {
+			return fmt.Sprintf("((%s) => %s)", params, ret.value.String())
+		}
 	}
 
+	return fmt.Sprintf("((%s) => %s)", params, NewBlockStmt(l.body, l.span).String())
+}
+
+func (l Lambda) Span() lexer.Span {
+	return l.span
+}
+
+func (l Lambda) Compute(env *Environment) (any, error) {
+	return newFunction("", l.params, l.body, env, false), nil
 }
 
 // An expression which groups another expression.
 type Group struct {
 	expr Expr
+	span lexer.Span
+}
+
+func NewGroup(expr Expr, span lexer.Span) Expr {
+	return Group{expr, span}
 }
 
-func NewGroup(expr Expr) Expr {
-	return Group{expr}
+func (g Group) Expr() Expr {
+	return g.expr
 }
 
 func (g Group) String() string {
 	return fmt.Sprintf("(%s)", g.expr.String())
 }
 
-func (g Group) Compute() (any, error) {
-	return g.expr.Compute()
+func (g Group) Span() lexer.Span {
+	return g.span
+}
+
+func (g Group) Compute(env *Environment) (any, error) {
+	return g.expr.Compute(env)
 }
 
 // Bottom level expression which wraps a native type.
 type Literal struct {
 	value any
+	span  lexer.Span
 }
 
-// Builds a new literal expression from lexeme and token kind.
+// Builds a new literal expression from lexeme and token kind, found at the given span of source.
 // It parses the raw string into the corresponding native type indicated by token kind.
-func NewLiteral(lexeme string, kind lexer.TokenKind) Expr {
+func NewLiteral(lexeme string, kind lexer.TokenKind, span lexer.Span) Expr {
 	var value any
 	var err error
 
@@ -133,13 +1261,139 @@ func NewLiteral(lexeme string, kind lexer.TokenKind) Expr {
 		panic(err)
 	}
 
-	return Literal{value}
+	return Literal{value, span}
+}
+
+func (l Literal) Value() any {
+	return l.value
 }
 
 func (l Literal) String() string {
-	return fmt.Sprintf("%v", l.value)
+	return stringify(l.value)
+}
+
+func (l Literal) Span() lexer.Span {
+	return l.span
 }
 
-func (l Literal) Compute() (any, error) {
+func (l Literal) Compute(env *Environment) (any, error) {
 	return l.value, nil
 }
+
+// An expression which reads the value bound to a variable name.
+type Variable struct {
+	name string
+	span lexer.Span
+}
+
+func NewVariable(name string, span lexer.Span) Expr {
+	return Variable{name, span}
+}
+
+func (v Variable) Name() string {
+	return v.name
+}
+
+func (v Variable) String() string {
+	return v.name
+}
+
+func (v Variable) Span() lexer.Span {
+	return v.span
+}
+
+func (v Variable) Compute(env *Environment) (any, error) {
+	value, err := env.Get(v.name)
+
+	return value, locate(err, v.span.Start)
+}
+
+// An expression which binds a new value to an already declared variable.
+// It results into the assigned value.
+type Assign struct {
+	name     string
+	value    Expr
+	nameSpan lexer.Span
+}
+
+func NewAssign(name string, value Expr, nameSpan lexer.Span) Expr {
+	return Assign{name, value, nameSpan}
+}
+
+func (a Assign) Name() string {
+	return a.name
+}
+
+func (a Assign) Value() Expr {
+	return a.value
+}
+
+func (a Assign) NameSpan() lexer.Span {
+	return a.nameSpan
+}
+
+func (a Assign) String() string {
+	return fmt.Sprintf("(%s = %s)", a.name, a.value.String())
+}
+
+func (a Assign) Span() lexer.Span {
+	return lexer.Join(a.nameSpan, a.value.Span())
+}
+
+func (a Assign) Compute(env *Environment) (any, error) {
+	value, err := a.value.Compute(env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := env.Assign(a.name, value); err != nil {
+		return nil, locate(err, a.nameSpan.Start)
+	}
+
+	return value, nil
+}
+
+// Placeholder for a missing or invalid expression, which lets the parser build a complete tree from
+// invalid source. Its span covers the invalid source, and it is empty where the expression was missing.
+//
+// Trees with placeholders come along with syntax errors, so they are never computed.
+type BadExpr struct {
+	span lexer.Span
+}
+
+func NewBadExpr(span lexer.Span) Expr {
+	return BadExpr{span}
+}
+
+func (b BadExpr) String() string {
+	return "<bad expression>"
+}
+
+func (b BadExpr) Span() lexer.Span {
+	return b.span
+}
+
+func (b BadExpr) Compute(env *Environment) (any, error) {
+	return nil, createASTErrorAt("can't compute invalid expression", b.span.Start)
+}
+
+// Marks the links of a chain which are optional, between their object and their brackets or parens.
+func optionalMark(optional bool) string {
+	if optional {
+		return "?."
+	}
+
+	return ""
+}
+
+// Joins the stringified versions of the given expressions with commas.
+func joinExprs(exprs []Expr) string {
+	parts := make([]string, len(exprs))
+
+	for i, expr := range exprs {
+		parts[i] = expr.String()
+	}
+
+	return strings.Join(parts, ", ")
+}
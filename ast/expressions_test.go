@@ -14,35 +14,36 @@ func TestExpressionsStrings(t *testing.T) {
 
 	tcs := []testCase{
 		{
-			expr:     NewBinary(NewLiteral("10", lexer.Number), lexer.Plus, NewLiteral("12", lexer.Number)),
+			expr:     NewBinary(NewLiteral("10", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Plus, 1), NewLiteral("12", lexer.Number, lexer.Span{})),
 			expected: "(10 + 12)",
 		},
 		{
-			expr:     NewUnary(lexer.Minus, NewLiteral("5", lexer.Number)),
+			expr:     NewUnary(lexer.MustCreateTokenFromKind(lexer.Minus, 1), NewLiteral("5", lexer.Number, lexer.Span{})),
 			expected: "(-5)",
 		},
 		{
-			expr:     NewGroup(NewLiteral("Hello world", lexer.String)),
+			expr:     NewGroup(NewLiteral("Hello world", lexer.String, lexer.Span{}), lexer.Span{}),
 			expected: "(Hello world)",
 		},
 		{
-			expr:     NewLiteral("20", lexer.Number),
+			expr:     NewLiteral("20", lexer.Number, lexer.Span{}),
 			expected: "20",
 		},
 		{
 			expr: NewGroup(
 				NewBinary(
-					NewLiteral("10", lexer.Number),
-					lexer.Star,
+					NewLiteral("10", lexer.Number, lexer.Span{}),
+					lexer.MustCreateTokenFromKind(lexer.Star, 1),
 					NewBinary(
 						NewUnary(
-							lexer.Minus,
-							NewLiteral("20", lexer.Number),
+							lexer.MustCreateTokenFromKind(lexer.Minus, 1),
+							NewLiteral("20", lexer.Number, lexer.Span{}),
 						),
-						lexer.Slash,
-						NewLiteral("8", lexer.Number),
+						lexer.MustCreateTokenFromKind(lexer.Slash, 1),
+						NewLiteral("8", lexer.Number, lexer.Span{}),
 					),
 				),
+				lexer.Span{},
 			),
 			expected: "((10 * ((-20) / 8)))",
 		},
@@ -65,25 +66,33 @@ func TestExpressionsComputing(t *testing.T) {
 
 	testCases := []testCase{
 		{
-			expr:     NewBinary(NewLiteral("10.0", lexer.Number), lexer.Slash, NewLiteral("5.0", lexer.Number)),
+			expr:     NewBinary(NewLiteral("10.0", lexer.Number, lexer.Span{}), lexer.MustCreateTokenFromKind(lexer.Slash, 1), NewLiteral("5.0", lexer.Number, lexer.Span{})),
 			expected: 2.0,
 		},
 		{
-			expr:     NewUnary(lexer.Minus, NewLiteral("10.0", lexer.Number)),
+			expr:     NewUnary(lexer.MustCreateTokenFromKind(lexer.Minus, 1), NewLiteral("10.0", lexer.Number, lexer.Span{})),
 			expected: -10.0,
 		},
 		{
-			expr:     NewGroup(NewLiteral("20.0", lexer.Number)),
+			expr:     NewGroup(NewLiteral("20.0", lexer.Number, lexer.Span{}), lexer.Span{}),
 			expected: 20.0,
 		},
 		{
-			expr:     NewLiteral("1.0", lexer.Number),
+			expr:     NewLiteral("1.0", lexer.Number, lexer.Span{}),
+			expected: 1.0,
+		},
+		{
+			expr:     NewConditional(NewLiteral("null", lexer.Null, lexer.Span{}), NewLiteral("1", lexer.Number, lexer.Span{}), NewLiteral("2", lexer.Number, lexer.Span{})),
+			expected: 2.0,
+		},
+		{
+			expr:     NewConditional(NewLiteral("0", lexer.Number, lexer.Span{}), NewLiteral("1", lexer.Number, lexer.Span{}), NewVariable("undefined", lexer.Span{})),
 			expected: 1.0,
 		},
 	}
 
 	for _, tc := range testCases {
-		got, _ := tc.expr.Compute()
+		got, _ := tc.expr.Compute(NewEnvironment(nil))
 
 		if tc.expected != got {
 			t.Errorf("expected %s, but got %s", tc.expected, got)
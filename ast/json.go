@@ -0,0 +1,489 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// Version of the JSON encoding of syntax trees. It changes whenever a node is encoded
+// differently, so cached trees from other versions are rejected instead of misread.
+const JSON_VERSION = 3
+
+// Top level JSON document, which wraps the encoded node with the encoding version.
+type jsonDocument struct {
+	Version int             `json:"version"`
+	Node    json.RawMessage `json:"node"`
+}
+
+// JSON object of a node. Keys are always sorted on encoding, so output is stable.
+type jsonObject map[string]any
+
+// Encodes a syntax tree, or any of its nodes, into versioned JSON.
+//
+// Every node is encoded as an object with its type, its span and its children by name.
+// Literal values are tagged with their kind, so they can be told apart on decoding.
+//
+// {"version": 3, "node": {"type": "Literal", "kind": "number", "value": 1, "span": {...}}}
+func EncodeJSON(node Node) ([]byte, error) {
+	encoded, err := json.Marshal(encodeNode(node))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonDocument{JSON_VERSION, encoded})
+}
+
+func encodeNode(node Node) jsonObject {
+	switch n := node.(type) {
+	case Program:
+		return jsonObject{"type": "Program", "span": n.Span(), "statements": encodeList(n.Statements)}
+
+	// Expressions
+	case Binary:
+		return jsonObject{"type": "Binary", "span": n.Span(), "left": encodeNode(n.left), "operator": n.operator.Lexeme, "operatorSpan": n.operator.Span(), "right": encodeNode(n.right)}
+	case Logical:
+		return jsonObject{"type": "Logical", "span": n.Span(), "left": encodeNode(n.left), "operator": n.operator.Lexeme, "operatorSpan": n.operator.Span(), "right": encodeNode(n.right)}
+	case Conditional:
+		return jsonObject{"type": "Conditional", "span": n.Span(), "condition": encodeNode(n.condition), "thenBranch": encodeNode(n.thenBranch), "elseBranch": encodeNode(n.elseBranch)}
+	case Unary:
+		return jsonObject{"type": "Unary", "span": n.Span(), "operator": n.operator.Lexeme, "operatorSpan": n.operator.Span(), "right": encodeNode(n.right)}
+	case Call:
+		return jsonObject{"type": "Call", "span": n.Span(), "callee": encodeNode(n.callee), "args": encodeList(n.args), "optional": n.optional, "paren": n.paren}
+	case NamedArgument:
+		return jsonObject{"type": "NamedArgument", "span": n.Span(), "name": n.name, "nameSpan": n.nameSpan, "value": encodeNode(n.value)}
+	case DestructuringAssign:
+		return jsonObject{"type": "DestructuringAssign", "span": n.Span(), "target": encodeNode(n.target), "value": encodeNode(n.value)}
+	case Match:
+		return jsonObject{"type": "Match", "span": n.span, "subject": encodeNode(n.subject), "arms": encodeList(n.arms)}
+	case MatchArm:
+		return jsonObject{"type": "MatchArm", "span": n.Span(), "pattern": encodeNode(n.pattern), "guard": encodeOptional(n.guard), "body": encodeNode(n.body)}
+	case Get:
+		return jsonObject{"type": "Get", "span": n.Span(), "object": encodeNode(n.object), "name": n.name, "optional": n.optional, "nameSpan": n.nameSpan}
+	case Set:
+		return jsonObject{"type": "Set", "span": n.Span(), "object": encodeNode(n.object), "name": n.name, "nameSpan": n.nameSpan, "value": encodeNode(n.value)}
+	case This:
+		return jsonObject{"type": "This", "span": n.span}
+	case Super:
+		return jsonObject{"type": "Super", "span": n.span, "method": n.method}
+	case ListLiteral:
+		return jsonObject{"type": "ListLiteral", "span": n.span, "elements": encodeList(n.elements)}
+	case Spread:
+		return jsonObject{"type": "Spread", "span": n.span, "expr": encodeNode(n.expr)}
+	case MapLiteral:
+		return jsonObject{"type": "MapLiteral", "span": n.span, "keys": encodeList(n.keys), "values": encodeList(n.values)}
+	case Index:
+		return jsonObject{"type": "Index", "span": n.Span(), "object": encodeNode(n.object), "index": encodeNode(n.index), "optional": n.optional, "brackets": n.brackets}
+	case IndexSet:
+		return jsonObject{"type": "IndexSet", "span": n.Span(), "object": encodeNode(n.object), "index": encodeNode(n.index), "value": encodeNode(n.value), "brackets": n.brackets}
+	case Slice:
+		return jsonObject{"type": "Slice", "span": n.Span(), "object": encodeNode(n.object), "start": encodeOptional(n.start), "end": encodeOptional(n.end), "optional": n.optional, "brackets": n.brackets}
+	case Lambda:
+		return jsonObject{"type": "Lambda", "span": n.span, "params": encodeList(n.params), "body": encodeList(n.body), "arrow": n.arrow}
+	case Group:
+		return jsonObject{"type": "Group", "span": n.span, "expr": encodeNode(n.expr)}
+	case Literal:
+		return jsonObject{"type": "Literal", "span": n.span, "kind": typeName(n.value), "value": n.value}
+	case Variable:
+		return jsonObject{"type": "Variable", "span": n.span, "name": n.name}
+	case Assign:
+		return jsonObject{"type": "Assign", "span": n.Span(), "name": n.name, "nameSpan": n.nameSpan, "value": encodeNode(n.value)}
+	case BadExpr:
+		return jsonObject{"type": "BadExpr", "span": n.span}
+
+	// Statements
+	case ExpressionStmt:
+		return jsonObject{"type": "ExpressionStmt", "span": n.span, "expr": encodeNode(n.expr)}
+	case PrintStmt:
+		return jsonObject{"type": "PrintStmt", "span": n.span, "expr": encodeNode(n.expr)}
+	case VarStmt:
+		return jsonObject{"type": "VarStmt", "span": n.span, "target": encodeNode(n.target), "initializer": encodeOptional(n.initializer)}
+	case BlockStmt:
+		return jsonObject{"type": "BlockStmt", "span": n.span, "statements": encodeList(n.statements)}
+	case IfStmt:
+		return jsonObject{"type": "IfStmt", "span": n.span, "condition": encodeNode(n.condition), "thenBranch": encodeNode(n.thenBranch), "elseBranch": encodeOptional(n.elseBranch)}
+	case WhileStmt:
+		return jsonObject{"type": "WhileStmt", "span": n.span, "condition": encodeNode(n.condition), "body": encodeNode(n.body), "label": n.label}
+	case ForStmt:
+		return jsonObject{"type": "ForStmt", "span": n.span, "initializer": encodeOptional(n.initializer), "condition": encodeOptional(n.condition), "increment": encodeOptional(n.increment), "body": encodeNode(n.body), "label": n.label}
+	case FunctionStmt:
+		return jsonObject{"type": "FunctionStmt", "span": n.span, "name": n.name, "params": encodeList(n.params), "body": encodeList(n.body)}
+	case ClassStmt:
+		var superclass Node
+
+		if n.superclass != nil {
+			superclass = *n.superclass
+		}
+
+		return jsonObject{"type": "ClassStmt", "span": n.span, "name": n.name, "superclass": encodeOptional(superclass), "methods": encodeList(n.methods)}
+	case ReturnStmt:
+		return jsonObject{"type": "ReturnStmt", "span": n.span, "value": encodeOptional(n.value)}
+	case BreakStmt:
+		return jsonObject{"type": "BreakStmt", "span": n.span, "label": n.label}
+	case ContinueStmt:
+		return jsonObject{"type": "ContinueStmt", "span": n.span, "label": n.label}
+	case BadStmt:
+		return jsonObject{"type": "BadStmt", "span": n.span}
+
+	// Patterns
+	case IdentifierPattern:
+		return jsonObject{"type": "IdentifierPattern", "span": n.span, "name": n.name}
+	case RestPattern:
+		return jsonObject{"type": "RestPattern", "span": n.span, "name": n.name}
+	case DefaultPattern:
+		return jsonObject{"type": "DefaultPattern", "span": n.Span(), "pattern": encodeNode(n.pattern), "value": encodeNode(n.value)}
+	case ListPattern:
+		return jsonObject{"type": "ListPattern", "span": n.span, "elements": encodeList(n.elements), "rest": encodeOptional(n.rest)}
+	case MapPattern:
+		return jsonObject{"type": "MapPattern", "span": n.span, "entries": encodeList(n.entries)}
+	case MapPatternEntry:
+		return jsonObject{"type": "MapPatternEntry", "span": n.Span(), "key": n.key, "keySpan": n.keySpan, "pattern": encodeNode(n.pattern)}
+	case WildcardPattern:
+		return jsonObject{"type": "WildcardPattern", "span": n.span}
+	case BadPattern:
+		return jsonObject{"type": "BadPattern", "span": n.span}
+	case LiteralPattern:
+		return jsonObject{"type": "LiteralPattern", "span": n.Span(), "literal": encodeNode(n.literal)}
+	case AlternativePattern:
+		return jsonObject{"type": "AlternativePattern", "span": n.Span(), "alternatives": encodeList(n.alternatives)}
+	case InstancePattern:
+		return jsonObject{"type": "InstancePattern", "span": n.Span(), "class": n.class, "classSpan": n.classSpan, "fields": encodeNode(n.fields)}
+	}
+
+	panic(fmt.Sprintf("unexpected node type %T", node))
+}
+
+// Encodes a node which can be missing. Missing nodes are encoded as null.
+func encodeOptional(node Node) any {
+	if node == nil {
+		return nil
+	}
+
+	return encodeNode(node)
+}
+
+func encodeList[N Node](nodes []N) []jsonObject {
+	encoded := make([]jsonObject, len(nodes))
+
+	for i, node := range nodes {
+		encoded[i] = encodeNode(node)
+	}
+
+	return encoded
+}
+
+// Exposes when, during decoding process, a JSON document doesn't describe a valid syntax tree.
+type decodeError struct {
+	msg string
+}
+
+func (e decodeError) Error() string {
+	return fmt.Sprintf("%s: invalid JSON syntax tree: %s", AST_PREFIX, e.msg)
+}
+
+// Decodes a syntax tree, or any of its nodes, from JSON produced by EncodeJSON.
+// Decoded tree is equivalent to the encoded one, so it can be printed or executed as if it was parsed.
+//
+// Documents encoded with another version are rejected.
+func DecodeJSON(data []byte) (node Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			decodeErr, ok := r.(decodeError)
+
+			if !ok {
+				panic(r)
+			}
+
+			node, err = nil, decodeErr
+		}
+	}()
+
+	var document jsonDocument
+
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, decodeError{err.Error()}
+	}
+
+	if document.Version != JSON_VERSION {
+		return nil, decodeError{fmt.Sprintf("unsupported version %d, expected %d", document.Version, JSON_VERSION)}
+	}
+
+	return decodeNode(document.Node), nil
+}
+
+// Fields of a JSON object of a node, decoded on demand.
+type jsonFields struct {
+	nodeType string
+	raw      map[string]json.RawMessage
+}
+
+func decodeNode(data json.RawMessage) Node {
+	var raw map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		panic(decodeError{err.Error()})
+	}
+
+	f := jsonFields{"node", raw}
+	f.nodeType = f.string("type")
+
+	switch f.nodeType {
+	case "Program":
+		return NewProgram(decodeList[Stmt](f, "statements"), make([]error, 0))
+
+	// Expressions
+	case "Binary":
+		return Binary{decodeAs[Expr](f, "left"), f.operator(binaryOperators...), decodeAs[Expr](f, "right")}
+	case "Logical":
+		return Logical{decodeAs[Expr](f, "left"), f.operator(logicalOperators...), decodeAs[Expr](f, "right")}
+	case "Conditional":
+		return Conditional{decodeAs[Expr](f, "condition"), decodeAs[Expr](f, "thenBranch"), decodeAs[Expr](f, "elseBranch")}
+	case "Unary":
+		return Unary{f.operator(unaryOperators...), decodeAs[Expr](f, "right")}
+	case "Call":
+		return Call{decodeAs[Expr](f, "callee"), decodeList[Expr](f, "args"), f.bool("optional"), f.span("paren")}
+	case "NamedArgument":
+		return NamedArgument{f.string("name"), f.span("nameSpan"), decodeAs[Expr](f, "value")}
+	case "DestructuringAssign":
+		return DestructuringAssign{decodeAs[Pattern](f, "target"), decodeAs[Expr](f, "value")}
+	case "Match":
+		return Match{decodeAs[Expr](f, "subject"), decodeList[MatchArm](f, "arms"), f.span("span")}
+	case "MatchArm":
+		return MatchArm{decodeAs[Pattern](f, "pattern"), decodeOptional[Expr](f, "guard"), decodeAs[Expr](f, "body")}
+	case "Get":
+		return Get{decodeAs[Expr](f, "object"), f.string("name"), f.bool("optional"), f.span("nameSpan")}
+	case "Set":
+		return Set{decodeAs[Expr](f, "object"), f.string("name"), decodeAs[Expr](f, "value"), f.span("nameSpan")}
+	case "This":
+		return This{f.span("span")}
+	case "Super":
+		return Super{f.string("method"), f.span("span")}
+	case "ListLiteral":
+		return ListLiteral{decodeList[Expr](f, "elements"), f.span("span")}
+	case "Spread":
+		return Spread{decodeAs[Expr](f, "expr"), f.span("span")}
+	case "MapLiteral":
+		keys, values := decodeList[Expr](f, "keys"), decodeList[Expr](f, "values")
+
+		if len(keys) != len(values) {
+			panic(decodeError{"MapLiteral has different amount of keys and values"})
+		}
+
+		return MapLiteral{keys, values, f.span("span")}
+	case "Index":
+		return Index{decodeAs[Expr](f, "object"), decodeAs[Expr](f, "index"), f.bool("optional"), f.span("brackets")}
+	case "IndexSet":
+		return IndexSet{decodeAs[Expr](f, "object"), decodeAs[Expr](f, "index"), decodeAs[Expr](f, "value"), f.span("brackets")}
+	case "Slice":
+		return Slice{decodeAs[Expr](f, "object"), decodeOptional[Expr](f, "start"), decodeOptional[Expr](f, "end"), f.bool("optional"), f.span("brackets")}
+	case "Lambda":
+		return Lambda{decodeList[Pattern](f, "params"), decodeList[Stmt](f, "body"), f.bool("arrow"), f.span("span")}
+	case "Group":
+		return Group{decodeAs[Expr](f, "expr"), f.span("span")}
+	case "Literal":
+		return Literal{f.literalValue(), f.span("span")}
+	case "Variable":
+		return Variable{f.string("name"), f.span("span")}
+	case "Assign":
+		return Assign{f.string("name"), decodeAs[Expr](f, "value"), f.span("nameSpan")}
+	case "BadExpr":
+		return BadExpr{f.span("span")}
+
+	// Statements
+	case "ExpressionStmt":
+		return ExpressionStmt{decodeAs[Expr](f, "expr"), f.span("span")}
+	case "PrintStmt":
+		return PrintStmt{decodeAs[Expr](f, "expr"), f.span("span")}
+	case "VarStmt":
+		return VarStmt{decodeAs[Pattern](f, "target"), decodeOptional[Expr](f, "initializer"), f.span("span")}
+	case "BlockStmt":
+		return BlockStmt{decodeList[Stmt](f, "statements"), f.span("span")}
+	case "IfStmt":
+		return IfStmt{decodeAs[Expr](f, "condition"), decodeAs[Stmt](f, "thenBranch"), decodeOptional[Stmt](f, "elseBranch"), f.span("span")}
+	case "WhileStmt":
+		return WhileStmt{decodeAs[Expr](f, "condition"), decodeAs[Stmt](f, "body"), f.string("label"), f.span("span")}
+	case "ForStmt":
+		return ForStmt{decodeOptional[Stmt](f, "initializer"), decodeOptional[Expr](f, "condition"), decodeOptional[Expr](f, "increment"), decodeAs[Stmt](f, "body"), f.string("label"), f.span("span")}
+	case "FunctionStmt":
+		return FunctionStmt{f.string("name"), decodeList[Pattern](f, "params"), decodeList[Stmt](f, "body"), f.span("span")}
+	case "ClassStmt":
+		var superclass *Variable
+
+		if f.has("superclass") {
+			variable := decodeAs[Variable](f, "superclass")
+			superclass = &variable
+		}
+
+		return ClassStmt{f.string("name"), superclass, decodeList[FunctionStmt](f, "methods"), f.span("span")}
+	case "ReturnStmt":
+		return ReturnStmt{decodeOptional[Expr](f, "value"), f.span("span")}
+	case "BreakStmt":
+		return BreakStmt{f.string("label"), f.span("span")}
+	case "ContinueStmt":
+		return ContinueStmt{f.string("label"), f.span("span")}
+	case "BadStmt":
+		return BadStmt{f.span("span")}
+
+	// Patterns
+	case "IdentifierPattern":
+		return IdentifierPattern{f.string("name"), f.span("span")}
+	case "RestPattern":
+		return RestPattern{f.string("name"), f.span("span")}
+	case "DefaultPattern":
+		return DefaultPattern{decodeAs[Pattern](f, "pattern"), decodeAs[Expr](f, "value")}
+	case "ListPattern":
+		return ListPattern{decodeList[Pattern](f, "elements"), decodeOptional[Pattern](f, "rest"), f.span("span")}
+	case "MapPattern":
+		return MapPattern{decodeList[MapPatternEntry](f, "entries"), f.span("span")}
+	case "MapPatternEntry":
+		return MapPatternEntry{f.string("key"), decodeAs[Pattern](f, "pattern"), f.span("keySpan")}
+	case "WildcardPattern":
+		return WildcardPattern{f.span("span")}
+	case "BadPattern":
+		return BadPattern{f.span("span")}
+	case "LiteralPattern":
+		return LiteralPattern{decodeAs[Literal](f, "literal")}
+	case "AlternativePattern":
+		alternatives := decodeList[Pattern](f, "alternatives")
+
+		if len(alternatives) == 0 {
+			panic(decodeError{"AlternativePattern without alternatives"})
+		}
+
+		return AlternativePattern{alternatives}
+	case "InstancePattern":
+		return InstancePattern{f.string("class"), decodeAs[MapPattern](f, "fields"), f.span("classSpan")}
+	}
+
+	panic(decodeError{fmt.Sprintf("unknown node type %q", f.nodeType)})
+}
+
+// Checks if the field is present and not null.
+func (f jsonFields) has(key string) bool {
+	raw, ok := f.raw[key]
+	return ok && string(raw) != "null"
+}
+
+// Decodes a required field into the given target.
+func (f jsonFields) decode(key string, target any) {
+	if !f.has(key) {
+		panic(decodeError{fmt.Sprintf("missing field %s of %s", key, f.nodeType)})
+	}
+
+	if err := json.Unmarshal(f.raw[key], target); err != nil {
+		panic(decodeError{fmt.Sprintf("field %s of %s: %s", key, f.nodeType, err.Error())})
+	}
+}
+
+func (f jsonFields) string(key string) string {
+	var value string
+	f.decode(key, &value)
+
+	return value
+}
+
+func (f jsonFields) bool(key string) bool {
+	var value bool
+	f.decode(key, &value)
+
+	return value
+}
+
+func (f jsonFields) span(key string) lexer.Span {
+	var value lexer.Span
+	f.decode(key, &value)
+
+	return value
+}
+
+// Operators each node type is built with by the parser, so decoding rejects any other.
+var (
+	binaryOperators = []lexer.TokenKind{
+		lexer.DoubleEqual, lexer.BangEqual, lexer.Greater, lexer.GreaterEqual, lexer.Less,
+		lexer.LessEqual, lexer.Plus, lexer.Minus, lexer.Star, lexer.Slash,
+	}
+	logicalOperators = []lexer.TokenKind{lexer.And, lexer.Or, lexer.DoubleQuestion}
+	unaryOperators   = []lexer.TokenKind{lexer.Bang, lexer.Minus}
+)
+
+// Decodes the operator token from its lexeme and span, which must be one of the allowed kinds.
+func (f jsonFields) operator(allowed ...lexer.TokenKind) lexer.Token {
+	lexeme := f.string("operator")
+	kind, ok := lexer.LexemeToTokenKindMap[lexeme]
+
+	if !ok {
+		panic(decodeError{fmt.Sprintf("unknown operator %q of %s", lexeme, f.nodeType)})
+	}
+
+	if !slices.Contains(allowed, kind) {
+		panic(decodeError{fmt.Sprintf("operator %q not allowed in %s", lexeme, f.nodeType)})
+	}
+
+	return lexer.CreateTokenAt(kind, lexeme, f.span("operatorSpan"))
+}
+
+// Decodes the value of a literal, following its kind.
+func (f jsonFields) literalValue() any {
+	switch kind := f.string("kind"); kind {
+	case "null":
+		return nil
+	case "number":
+		var value float64
+		f.decode("value", &value)
+
+		return value
+	case "string":
+		return f.string("value")
+	case "boolean":
+		return f.bool("value")
+	default:
+		panic(decodeError{fmt.Sprintf("unknown literal kind %q", kind)})
+	}
+}
+
+// Decodes a required child node, which must be of type N.
+func decodeAs[N any](f jsonFields, key string) N {
+	if !f.has(key) {
+		panic(decodeError{fmt.Sprintf("missing field %s of %s", key, f.nodeType)})
+	}
+
+	return expectNode[N](f, key, decodeNode(f.raw[key]))
+}
+
+// Decodes an optional child node, which must be of type N when it is present.
+func decodeOptional[N any](f jsonFields, key string) N {
+	if !f.has(key) {
+		var missing N
+		return missing
+	}
+
+	return decodeAs[N](f, key)
+}
+
+// Decodes a required list of child nodes, which must be of type N.
+func decodeList[N any](f jsonFields, key string) []N {
+	var raw []json.RawMessage
+	f.decode(key, &raw)
+
+	nodes := make([]N, len(raw))
+
+	for i, element := range raw {
+		nodes[i] = expectNode[N](f, key, decodeNode(element))
+	}
+
+	return nodes
+}
+
+func expectNode[N any](f jsonFields, key string, node Node) N {
+	expected, ok := node.(N)
+
+	if !ok {
+		panic(decodeError{fmt.Sprintf("field %s of %s expected %s, got %T", key, f.nodeType, reflect.TypeFor[N]().Name(), node)})
+	}
+
+	return expected
+}
@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("should round trip every node through JSON", func(t *testing.T) {
+		source := `
+			class Point < Base {
+				init(x, y) { this.x = x; this.y = -y; }
+				norm() { return super.norm() * 2; }
+			}
+			function f([a, b = 1], {c: d, "e": [e]}) {
+				for (var i = 0; i < 10 and !done; i = i + 1) {
+					if (i == 2) print i; else xs[i] = i;
+				}
+				while (null or false) print "never";
+				outer: while (true) { for (;;) { if (a) break outer; continue; } break; }
+				return a ? b : c;
+			}
+			var [first, ...others] = [1, "two", true, null];
+			var m = {"k": (1 + 2) * 3};
+			[first, second] = m["k"][1:];
+			print (x) => x + 1;
+			var g = function (y) { return y; };
+			print match p {
+				Point{x: 0} | 1 if ready => "origin",
+				"a" | _ => "other",
+			};
+			p.x = p.y ?? q?.y?.(1)?.[0]?.[:2];
+			f(1, 2, ...[3, ...rest]);
+			var h = (a, b = 1, ...more) => more;
+			h(1, b: 2);
+		`
+		program, errs := Parse(source)
+		assert.Empty(t, errs)
+
+		encoded, err := EncodeJSON(program)
+		assert.NoError(t, err)
+
+		decoded, err := DecodeJSON(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, program.String(), decoded.String())
+		assert.Equal(t, program.Span(), decoded.(Program).Span())
+
+		reencoded, _ := EncodeJSON(decoded)
+		assert.Equal(t, string(encoded), string(reencoded))
+	})
+
+	t.Run("should round trip placeholders of invalid source", func(t *testing.T) {
+		program, errs := Parse("print 1 + ; class 1 {}")
+		assert.Len(t, errs, 2)
+
+		encoded, err := EncodeJSON(program)
+		assert.NoError(t, err)
+
+		decoded, err := DecodeJSON(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, program, decoded)
+	})
+
+	t.Run("should decode trees which can be executed", func(t *testing.T) {
+		program, _ := Parse(`var xs = [1, 2]; print xs[0] + xs[1] + 0.5; print "gox" + "!"; print null == false;`)
+		encoded, _ := EncodeJSON(program)
+		decoded, err := DecodeJSON(encoded)
+		assert.NoError(t, err)
+
+		var output bytes.Buffer
+		assert.NoError(t, decoded.(Program).Execute(NewGlobalEnvironment(&output)))
+		assert.Equal(t, "3.5\ngox!\nfalse\n", output.String())
+	})
+
+	t.Run("should tag literals with their kind", func(t *testing.T) {
+		program, _ := Parse(`print 1; print "1"; print true; print null;`)
+		encoded, _ := EncodeJSON(program)
+
+		for _, kind := range []string{`"kind":"number","span"`, `"kind":"string","span"`, `"kind":"boolean","span"`, `"kind":"null","span"`} {
+			assert.Contains(t, string(encoded), kind)
+		}
+
+		assert.True(t, strings.HasPrefix(string(encoded), fmt.Sprintf(`{"version":%d,"node":{`, JSON_VERSION)))
+	})
+
+	t.Run("should reject invalid documents", func(t *testing.T) {
+		document := func(node string) string {
+			return fmt.Sprintf(`{"version":%d,"node":%s}`, JSON_VERSION, node)
+		}
+		testCases := map[string]string{
+			`{"version":1,"node":{}}`:                               fmt.Sprintf("[AST]: invalid JSON syntax tree: unsupported version 1, expected %d", JSON_VERSION),
+			fmt.Sprintf(`{"version":%d,"node":{}}`, JSON_VERSION+1): fmt.Sprintf("[AST]: invalid JSON syntax tree: unsupported version %d, expected %d", JSON_VERSION+1, JSON_VERSION),
+			document(`{"type":"Nope"}`):                             `[AST]: invalid JSON syntax tree: unknown node type "Nope"`,
+			document(`{"type":"Group","span":{}}`):                  "[AST]: invalid JSON syntax tree: missing field expr of Group",
+			document(`{"type":"PrintStmt","span":{},"expr":{"type":"WildcardPattern","span":{}}}`):                                                  "[AST]: invalid JSON syntax tree: field expr of PrintStmt expected Expr, got ast.WildcardPattern",
+			document(`{"type":"Literal","span":{},"kind":"date","value":1}`):                                                                        `[AST]: invalid JSON syntax tree: unknown literal kind "date"`,
+			document(`{"type":"Get","span":{},"object":{"type":"This","span":{}},"name":"a","nameSpan":{}}`):                                        "[AST]: invalid JSON syntax tree: missing field optional of Get",
+			document(`{"type":"BreakStmt","span":{}}`):                                                                                              "[AST]: invalid JSON syntax tree: missing field label of BreakStmt",
+			document(`{"type":"Unary","span":{},"operator":"and","operatorSpan":{}}`):                                                               `[AST]: invalid JSON syntax tree: operator "and" not allowed in Unary`,
+			document(`{"type":"Binary","span":{},"left":{"type":"Literal","span":{},"kind":"number","value":1},"operator":"=>","operatorSpan":{}}`): `[AST]: invalid JSON syntax tree: operator "=>" not allowed in Binary`,
+		}
+
+		for document, expected := range testCases {
+			_, err := DecodeJSON([]byte(document))
+			assert.EqualError(t, err, expected)
+		}
+	})
+}
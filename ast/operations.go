@@ -39,3 +39,13 @@ func computeNumberBinaryOperation(left float64, operator lexer.TokenKind, right
 		return 0, createASTError(fmt.Sprintf("invalid operator %s for numeric binary operation", lexer.TokenKindToLexemeMap[operator]))
 	}
 }
+
+// Computes the result of the binary operation corresponding to given operator and strings
+func computeStringBinaryOperation(left string, operator lexer.TokenKind, right string) (any, error) {
+	switch operator {
+	case lexer.Plus:
+		return left + right, nil
+	default:
+		return nil, createASTError(fmt.Sprintf("invalid operator %s for string binary operation", lexer.TokenKindToLexemeMap[operator]))
+	}
+}
@@ -0,0 +1,60 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// Tokenizes and parses the given source code into a program.
+//
+// Returned errors include both tokenization and syntax errors. Even when errors are
+// returned, the program holds every statement, with placeholders where source is invalid.
+func Parse(source string) (Program, []error) {
+	l := lexer.New(source)
+	tokens, lexErrors := l.Tokenize()
+	program, parseErrors := ParseProgram(tokens)
+
+	return program, append(lexErrors, parseErrors...)
+}
+
+// Parses the given tokens stream into a program. Tokens stream must end with an Eof token.
+//
+// A complete tree is returned even when there are syntax errors: missing expressions and
+// numbers out of range are replaced by BadExpr nodes (BadPattern nodes within patterns),
+// missing semicolons and closing delimiters are assumed in place, and statements which
+// can't be completed are replaced by BadStmt nodes.
+func ParseProgram(tokens []lexer.Token) (Program, []error) {
+	ast := New(tokens)
+	program := ast.program()
+
+	return program, ast.errors
+}
+
+// Parses the given tokens stream as a single expression, such as the ones typed into a REPL.
+// Tokens stream must end with an Eof token and it must not contain anything after the expression.
+func ParseExpr(tokens []lexer.Token) (expr Expr, errs []error) {
+	ast := New(tokens)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(syntaxError)
+
+			if !ok {
+				panic(r)
+			}
+
+			expr = nil
+			errs = append(ast.errors, err)
+		}
+	}()
+
+	expr = ast.expr()
+
+	if !ast.isEnd() {
+		token := ast.peek()
+		panic(newSyntaxError(fmt.Sprintf("unexpected token (%s) after expression", token.Lexeme), token.Span().Start))
+	}
+
+	return expr, ast.errors
+}
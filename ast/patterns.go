@@ -0,0 +1,599 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// A pattern describes the shape of a value and the names its parts are bound to.
+// Patterns are used as targets of variable declarations, assignments and function parameters.
+type Pattern interface {
+	String() string   // Exposes stringified version of the pattern.
+	Span() lexer.Span // Exposes the range of source the pattern was parsed from.
+	// Destructures the value, calling bind for every name within the pattern.
+	// Default values are computed within the given environment.
+	bind(env *Environment, value any, bind binder) error
+}
+
+// Binds a value to a name. Declarations define new variables, while assignments update existing ones.
+type binder func(name string, value any) error
+
+// Creates a binder which declares every name at the given environment.
+func declareInto(env *Environment) binder {
+	return func(name string, value any) error {
+		env.Define(name, value)
+		return nil
+	}
+}
+
+// Creates a binder which assigns every name through the given environment scopes chain.
+func assignInto(env *Environment) binder {
+	return env.Assign
+}
+
+// A pattern which binds the whole value to a single name.
+type IdentifierPattern struct {
+	name string
+	span lexer.Span
+}
+
+func NewIdentifierPattern(name string, span lexer.Span) Pattern {
+	return IdentifierPattern{name, span}
+}
+
+func (p IdentifierPattern) Name() string {
+	return p.name
+}
+
+func (p IdentifierPattern) String() string {
+	return p.name
+}
+
+func (p IdentifierPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p IdentifierPattern) bind(env *Environment, value any, bind binder) error {
+	return locate(bind(p.name, value), p.span.Start)
+}
+
+// A pattern which provides a fallback value for a missing list element or map key.
+// Default value is only computed when it is required.
+type DefaultPattern struct {
+	pattern Pattern
+	value   Expr
+}
+
+func NewDefaultPattern(pattern Pattern, value Expr) Pattern {
+	return DefaultPattern{pattern, value}
+}
+
+func (p DefaultPattern) Pattern() Pattern {
+	return p.pattern
+}
+
+func (p DefaultPattern) Value() Expr {
+	return p.value
+}
+
+func (p DefaultPattern) String() string {
+	return fmt.Sprintf("%s = %s", p.pattern.String(), p.value.String())
+}
+
+func (p DefaultPattern) Span() lexer.Span {
+	return lexer.Join(p.pattern.Span(), p.value.Span())
+}
+
+func (p DefaultPattern) bind(env *Environment, value any, bind binder) error {
+	return p.pattern.bind(env, value, bind)
+}
+
+// Binds the nested pattern to the computed default value.
+func (p DefaultPattern) bindDefault(env *Environment, bind binder) error {
+	value, err := p.value.Compute(env)
+
+	if err != nil {
+		return err
+	}
+
+	return p.pattern.bind(env, value, bind)
+}
+
+// A parameter pattern which collects the remaining arguments of a call into a new list.
+// Only allowed as the last parameter of a function.
+//
+// function log(level, ...parts) {}
+type RestPattern struct {
+	name string
+	span lexer.Span
+}
+
+func NewRestPattern(name string, span lexer.Span) Pattern {
+	return RestPattern{name, span}
+}
+
+func (p RestPattern) Name() string {
+	return p.name
+}
+
+func (p RestPattern) String() string {
+	return "..." + p.name
+}
+
+func (p RestPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p RestPattern) bind(env *Environment, value any, bind binder) error {
+	return locate(bind(p.name, value), p.span.Start)
+}
+
+// A pattern which destructures a list element by element.
+//
+// Without rest, list must have as many elements as the pattern. With rest, remaining
+// elements are collected into a new list bound to it.
+//
+// [first, second = 2, ...rest]
+type ListPattern struct {
+	elements []Pattern
+	rest     Pattern // nil when pattern doesn't collect remaining elements
+	span     lexer.Span
+}
+
+func NewListPattern(elements []Pattern, rest Pattern, span lexer.Span) Pattern {
+	return ListPattern{elements, rest, span}
+}
+
+func (p ListPattern) Elements() []Pattern {
+	return p.elements
+}
+
+func (p ListPattern) Rest() Pattern {
+	return p.rest
+}
+
+func (p ListPattern) String() string {
+	parts := make([]string, len(p.elements))
+
+	for i, element := range p.elements {
+		parts[i] = element.String()
+	}
+
+	if p.rest != nil {
+		parts = append(parts, "..."+p.rest.String())
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(parts, ", "))
+}
+
+func (p ListPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p ListPattern) bind(env *Environment, value any, bind binder) error {
+	list, ok := value.(*List)
+
+	if !ok {
+		return newPatternMismatchError(fmt.Sprintf("can't destructure value of type %s as list", typeName(value)), p.span.Start)
+	}
+
+	length := len(list.elements)
+	required := p.requiredElements()
+
+	if length < required || (p.rest == nil && length > len(p.elements)) {
+		return newPatternMismatchError(fmt.Sprintf("expected list of %s elements, got %d", p.expectedLength(), length), p.span.Start)
+	}
+
+	for i, element := range p.elements {
+		var err error
+
+		if i < length {
+			err = element.bind(env, list.elements[i], bind)
+		} else {
+			err = element.(DefaultPattern).bindDefault(env, bind)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.rest == nil {
+		return nil
+	}
+
+	rest := make([]any, 0)
+
+	if length > len(p.elements) {
+		rest = append(rest, list.elements[len(p.elements):]...)
+	}
+
+	return p.rest.bind(env, newList(rest), bind)
+}
+
+// Amount of elements a list needs to be destructured: every element up to the last one without default.
+func (p ListPattern) requiredElements() int {
+	for i := len(p.elements) - 1; i >= 0; i-- {
+		if _, ok := p.elements[i].(DefaultPattern); !ok {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// Describes the lengths of the lists which can be destructured by the pattern.
+func (p ListPattern) expectedLength() string {
+	required := p.requiredElements()
+
+	switch {
+	case p.rest != nil:
+		return fmt.Sprintf("at least %d", required)
+	case required == len(p.elements):
+		return fmt.Sprintf("%d", required)
+	default:
+		return fmt.Sprintf("%d to %d", required, len(p.elements))
+	}
+}
+
+// An entry of a map pattern. Its pattern is bound to the value of the key.
+type MapPatternEntry struct {
+	key     string
+	pattern Pattern
+	keySpan lexer.Span
+}
+
+func (e MapPatternEntry) Key() string {
+	return e.key
+}
+
+func (e MapPatternEntry) KeySpan() lexer.Span {
+	return e.keySpan
+}
+
+func (e MapPatternEntry) Span() lexer.Span {
+	return lexer.Join(e.keySpan, e.pattern.Span())
+}
+
+func (e MapPatternEntry) Pattern() Pattern {
+	return e.pattern
+}
+
+// A pattern which destructures a map, or the fields of an instance, key by key.
+// Keys not named by the pattern are ignored.
+//
+// {host, port = 80, server: {name}, "content-type": contentType}
+type MapPattern struct {
+	entries []MapPatternEntry
+	span    lexer.Span
+}
+
+func NewMapPattern(entries []MapPatternEntry, span lexer.Span) Pattern {
+	return MapPattern{entries, span}
+}
+
+func (p MapPattern) Entries() []MapPatternEntry {
+	return p.entries
+}
+
+func (p MapPattern) String() string {
+	parts := make([]string, len(p.entries))
+
+	for i, entry := range p.entries {
+		parts[i] = entry.String()
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
+}
+
+func (p MapPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p MapPattern) bind(env *Environment, value any, bind binder) error {
+	lookup, err := p.lookup(value)
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range p.entries {
+		value, ok := lookup(entry.key)
+
+		if ok {
+			err = entry.pattern.bind(env, value, bind)
+		} else if defaultPattern, hasDefault := entry.pattern.(DefaultPattern); hasDefault {
+			err = defaultPattern.bindDefault(env, bind)
+		} else {
+			err = newPatternMismatchError(fmt.Sprintf("missing key %s", stringifyNested(entry.key)), entry.keySpan.Start)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Builds a function to look up keys over the destructured value, which is either a map or an instance.
+func (p MapPattern) lookup(value any) (func(key string) (any, bool), error) {
+	switch v := value.(type) {
+	case *Map:
+		return func(key string) (any, bool) {
+			element, ok := v.values[key]
+			return element, ok
+		}, nil
+	case *Instance:
+		return func(key string) (any, bool) {
+			element, err := v.Get(key)
+			return element, err == nil
+		}, nil
+	default:
+		return nil, newPatternMismatchError(fmt.Sprintf("can't destructure value of type %s as map", typeName(value)), p.span.Start)
+	}
+}
+
+// Stringifies the entry using shorthand syntax when the key is bound to a variable of the same name.
+func (e MapPatternEntry) String() string {
+	switch pattern := e.pattern.(type) {
+	case IdentifierPattern:
+		if pattern.name == e.key {
+			return e.key
+		}
+	case DefaultPattern:
+		if identifier, ok := pattern.pattern.(IdentifierPattern); ok && identifier.name == e.key {
+			return pattern.String()
+		}
+	}
+
+	return fmt.Sprintf("%s: %s", stringifyKey(e.key), e.pattern.String())
+}
+
+// A pattern which matches any value without binding it. Only allowed within match arms.
+type WildcardPattern struct {
+	span lexer.Span
+}
+
+func NewWildcardPattern(span lexer.Span) Pattern {
+	return WildcardPattern{span}
+}
+
+func (p WildcardPattern) String() string {
+	return "_"
+}
+
+func (p WildcardPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p WildcardPattern) bind(env *Environment, value any, bind binder) error {
+	return nil
+}
+
+// Placeholder for an invalid pattern, which lets the parser build a complete tree from invalid source.
+//
+// Trees with placeholders come along with syntax errors, so they are never bound.
+type BadPattern struct {
+	span lexer.Span
+}
+
+func NewBadPattern(span lexer.Span) Pattern {
+	return BadPattern{span}
+}
+
+func (p BadPattern) String() string {
+	return "<bad pattern>"
+}
+
+func (p BadPattern) Span() lexer.Span {
+	return p.span
+}
+
+func (p BadPattern) bind(env *Environment, value any, bind binder) error {
+	return createASTErrorAt("can't bind invalid pattern", p.span.Start)
+}
+
+// A pattern which matches values equal to a literal. Only allowed within match arms.
+type LiteralPattern struct {
+	literal Literal
+}
+
+func NewLiteralPattern(literal Literal) Pattern {
+	return LiteralPattern{literal}
+}
+
+func (p LiteralPattern) Literal() Literal {
+	return p.literal
+}
+
+func (p LiteralPattern) String() string {
+	return stringifyNested(p.literal.value)
+}
+
+func (p LiteralPattern) Span() lexer.Span {
+	return p.literal.span
+}
+
+func (p LiteralPattern) bind(env *Environment, value any, bind binder) error {
+	if !isEqual(p.literal.value, value) {
+		return newPatternMismatchError(fmt.Sprintf("expected %s, got %s", p.String(), stringifyNested(value)), p.literal.span.Start)
+	}
+
+	return nil
+}
+
+// A pattern which matches when any of its alternatives matches, trying them in order.
+// Only allowed within match arms.
+//
+// 1 | 2 | 3
+type AlternativePattern struct {
+	alternatives []Pattern
+}
+
+func NewAlternativePattern(alternatives []Pattern) Pattern {
+	return AlternativePattern{alternatives}
+}
+
+func (p AlternativePattern) Alternatives() []Pattern {
+	return p.alternatives
+}
+
+func (p AlternativePattern) String() string {
+	parts := make([]string, len(p.alternatives))
+
+	for i, alternative := range p.alternatives {
+		parts[i] = alternative.String()
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func (p AlternativePattern) Span() lexer.Span {
+	return lexer.Join(p.alternatives[0].Span(), p.alternatives[len(p.alternatives)-1].Span())
+}
+
+func (p AlternativePattern) bind(env *Environment, value any, bind binder) error {
+	var err error
+
+	for _, alternative := range p.alternatives {
+		err = alternative.bind(env, value, bind)
+
+		if _, isMismatch := err.(patternMismatchError); !isMismatch {
+			return err
+		}
+	}
+
+	return err
+}
+
+// A pattern which matches instances of a class, or of any of its subclasses, destructuring their fields.
+// Only allowed within match arms.
+//
+// User {name, role: "admin"}
+type InstancePattern struct {
+	class     string
+	fields    MapPattern
+	classSpan lexer.Span
+}
+
+func NewInstancePattern(class string, fields MapPattern, classSpan lexer.Span) Pattern {
+	return InstancePattern{class, fields, classSpan}
+}
+
+func (p InstancePattern) Class() string {
+	return p.class
+}
+
+func (p InstancePattern) Fields() MapPattern {
+	return p.fields
+}
+
+func (p InstancePattern) String() string {
+	return fmt.Sprintf("%s %s", p.class, p.fields.String())
+}
+
+func (p InstancePattern) ClassSpan() lexer.Span {
+	return p.classSpan
+}
+
+func (p InstancePattern) Span() lexer.Span {
+	return lexer.Join(p.classSpan, p.fields.span)
+}
+
+func (p InstancePattern) bind(env *Environment, value any, bind binder) error {
+	expected, err := env.Get(p.class)
+
+	if err != nil {
+		return locate(err, p.classSpan.Start)
+	}
+
+	class, ok := expected.(*Class)
+
+	if !ok {
+		return createASTErrorAt(fmt.Sprintf("can't match instances of value of type %s", typeName(expected)), p.classSpan.Start)
+	}
+
+	instance, ok := value.(*Instance)
+
+	if !ok || !instance.isInstanceOf(class) {
+		return newPatternMismatchError(fmt.Sprintf("expected instance of %s, got %s", class.String(), stringify(value)), p.classSpan.Start)
+	}
+
+	return p.fields.bind(env, instance, bind)
+}
+
+// Checks if a pattern matches any value, so arms after it can't be reached.
+func isIrrefutable(pattern Pattern) bool {
+	switch p := pattern.(type) {
+	case WildcardPattern, IdentifierPattern:
+		return true
+	case AlternativePattern:
+		for _, alternative := range p.alternatives {
+			if isIrrefutable(alternative) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Checks if a pattern could match the given literal value. Only literal patterns can be
+// decided statically; any other refutable pattern is assumed to possibly match.
+func canMatchLiteral(pattern Pattern, value any) bool {
+	switch p := pattern.(type) {
+	case LiteralPattern:
+		return isEqual(p.literal.value, value)
+	case AlternativePattern:
+		for _, alternative := range p.alternatives {
+			if canMatchLiteral(alternative, value) {
+				return true
+			}
+		}
+
+		return false
+	case ListPattern, MapPattern, InstancePattern:
+		return false
+	default:
+		return true
+	}
+}
+
+// Stringifies a map pattern key, quoting it when it isn't a valid identifier.
+func stringifyKey(key string) string {
+	if _, isReserved := lexer.LexemeToTokenKindMap[key]; isReserved || !isIdentifier(key) {
+		return stringifyNested(key)
+	}
+
+	return key
+}
+
+// Checks if the given text follows identifiers lexical rules.
+func isIdentifier(text string) bool {
+	for i, ch := range text {
+		if i == 0 && !unicode.IsLetter(ch) {
+			return false
+		}
+
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' {
+			return false
+		}
+	}
+
+	return text != ""
+}
+
+// Joins the stringified versions of the given patterns with commas.
+func joinPatterns(patterns []Pattern) string {
+	parts := make([]string, len(patterns))
+
+	for i, pattern := range patterns {
+		parts[i] = pattern.String()
+	}
+
+	return strings.Join(parts, ", ")
+}
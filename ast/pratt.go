@@ -0,0 +1,670 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// Binding power of an operator. Operators with higher precedence bind tighter to their operands.
+type precedence int
+
+const (
+	assignmentPrecedence precedence = iota
+	conditionalPrecedence
+	coalescePrecedence
+	orPrecedence
+	andPrecedence
+	equalityPrecedence
+	comparisonPrecedence
+	termPrecedence
+	factorPrecedence
+	unaryPrecedence
+	callPrecedence
+)
+
+// Determines how operators of the same precedence are grouped.
+// Left associative: a - b - c is (a - b) - c. Right associative: a = b = c is a = (b = c).
+type associativity int
+
+const (
+	leftAssociative associativity = iota
+	rightAssociative
+)
+
+// Parses an expression which starts with the given token. Token is already consumed.
+type prefixParselet func(ast *AST, token lexer.Token) Expr
+
+// Parses an expression which continues the already parsed left operand with the given operator,
+// and a right operand parsed with the given precedence. Operator is already consumed.
+type infixParselet func(ast *AST, left Expr, operator lexer.Token, operand precedence) Expr
+
+// Parses an expression which continues the already parsed left operand with the given operator,
+// without any right operand (calls, property access, indexes). Operator is already consumed.
+type postfixParselet func(ast *AST, left Expr, operator lexer.Token) Expr
+
+type infixRule struct {
+	precedence    precedence
+	associativity associativity
+	parse         infixParselet
+}
+
+// Precedence of the right operand. Left associative operators require it to bind tighter,
+// so same precedence operators are grouped from the left.
+func (r infixRule) operandPrecedence() precedence {
+	if r.associativity == leftAssociative {
+		return r.precedence + 1
+	}
+
+	return r.precedence
+}
+
+type postfixRule struct {
+	precedence precedence
+	parse      postfixParselet
+}
+
+// Parselets tables indexed by the token kind which triggers them.
+//
+// Adding an operator only requires a new entry here, and its evaluation at runtime.
+var (
+	prefixRules  map[lexer.TokenKind]prefixParselet
+	infixRules   map[lexer.TokenKind]infixRule
+	postfixRules map[lexer.TokenKind]postfixRule
+)
+
+// Tables are built at init, because parselets refer back to them through parsePrecedence.
+func init() {
+	prefixRules = map[lexer.TokenKind]prefixParselet{
+		lexer.Number:      (*AST).literal,
+		lexer.String:      (*AST).literal,
+		lexer.True:        (*AST).literal,
+		lexer.False:       (*AST).literal,
+		lexer.Null:        (*AST).literal,
+		lexer.Identifier:  (*AST).variable,
+		lexer.This:        (*AST).this,
+		lexer.Super:       (*AST).super,
+		lexer.Function:    (*AST).lambda,
+		lexer.LeftParen:   (*AST).groupOrArrowFunction,
+		lexer.LeftBracket: (*AST).listLiteral,
+		lexer.LeftBrace:   (*AST).mapLiteral,
+		lexer.Match:       (*AST).matchExpression,
+		lexer.Minus:       (*AST).unary,
+		lexer.Bang:        (*AST).unary,
+	}
+
+	infixRules = map[lexer.TokenKind]infixRule{
+		lexer.Equal:          {assignmentPrecedence, rightAssociative, (*AST).assignment},
+		lexer.Question:       {conditionalPrecedence, rightAssociative, (*AST).conditional},
+		lexer.DoubleQuestion: {coalescePrecedence, leftAssociative, (*AST).logical},
+		lexer.Or:             {orPrecedence, leftAssociative, (*AST).logical},
+		lexer.And:            {andPrecedence, leftAssociative, (*AST).logical},
+		lexer.DoubleEqual:    {equalityPrecedence, leftAssociative, (*AST).binary},
+		lexer.BangEqual:      {equalityPrecedence, leftAssociative, (*AST).binary},
+		lexer.Greater:        {comparisonPrecedence, leftAssociative, (*AST).binary},
+		lexer.GreaterEqual:   {comparisonPrecedence, leftAssociative, (*AST).binary},
+		lexer.Less:           {comparisonPrecedence, leftAssociative, (*AST).binary},
+		lexer.LessEqual:      {comparisonPrecedence, leftAssociative, (*AST).binary},
+		lexer.Plus:           {termPrecedence, leftAssociative, (*AST).binary},
+		lexer.Minus:          {termPrecedence, leftAssociative, (*AST).binary},
+		lexer.Star:           {factorPrecedence, leftAssociative, (*AST).binary},
+		lexer.Slash:          {factorPrecedence, leftAssociative, (*AST).binary},
+	}
+
+	postfixRules = map[lexer.TokenKind]postfixRule{
+		lexer.LeftParen:   {callPrecedence, (*AST).call},
+		lexer.Dot:         {callPrecedence, (*AST).get},
+		lexer.LeftBracket: {callPrecedence, (*AST).indexOrSlice},
+		lexer.QuestionDot: {callPrecedence, (*AST).optionalChain},
+	}
+}
+
+// Parses an expression with the lowest precedence, so any operator is allowed within it.
+func (ast *AST) expr() Expr {
+	return ast.parsePrecedence(assignmentPrecedence)
+}
+
+// Parses an expression whose operators have, at least, the given precedence.
+//
+// It starts from the prefix parselet of the current token, and then keeps extending the
+// parsed expression with the postfix and infix parselets of the following tokens, while
+// their precedence is high enough.
+func (ast *AST) parsePrecedence(min precedence) Expr {
+	// Destructuring patterns start like list and map literals; they are only allowed where an assignment is.
+	if min <= assignmentPrecedence && ast.isPatternAssignment() {
+		return ast.patternAssignment()
+	}
+
+	token := ast.peek()
+	prefix, ok := prefixRules[token.Kind]
+
+	if !ok {
+		err := newSyntaxError(fmt.Sprintf("expected expression, found %s", describeToken(token)), token.Span().Start)
+
+		if !ast.atBoundary() {
+			panic(err)
+		}
+
+		ast.registerMissing(err)
+
+		return NewBadExpr(lexer.Span{Start: token.Span().Start, End: token.Span().Start})
+	}
+
+	ast.advance()
+	left := prefix(ast, token)
+
+	for {
+		token = ast.peek()
+
+		if rule, ok := postfixRules[token.Kind]; ok && rule.precedence >= min {
+			ast.advance()
+			left = rule.parse(ast, left, token)
+			continue
+		}
+
+		if rule, ok := infixRules[token.Kind]; ok && rule.precedence >= min {
+			ast.advance()
+			left = rule.parse(ast, left, token, rule.operandPrecedence())
+			continue
+		}
+
+		return left
+	}
+}
+
+// Literal expression holds the value of a number, string, boolean or null token.
+// Numbers which can't be represented are reported, and replaced by a placeholder.
+func (ast *AST) literal(token lexer.Token) Expr {
+	if !ast.checkNumber(token.Lexeme, token) {
+		return NewBadExpr(token.Span())
+	}
+
+	return NewLiteral(token.Lexeme, token.Kind, token.Span())
+}
+
+// Checks that the lexeme of a number token fits a float, reporting it at the token otherwise.
+// Tokens of any other kind are always valid.
+func (ast *AST) checkNumber(lexeme string, token lexer.Token) bool {
+	if token.Kind != lexer.Number {
+		return true
+	}
+
+	if _, err := strconv.ParseFloat(lexeme, 64); err != nil {
+		ast.registerError(newSyntaxError("number is out of range", token.Span().Start))
+		return false
+	}
+
+	return true
+}
+
+// Variable expression references a variable by its name.
+func (ast *AST) variable(token lexer.Token) Expr {
+	return NewVariable(token.Lexeme, token.Span())
+}
+
+// This expression references the instance a method is bound to. It is only allowed within classes.
+func (ast *AST) this(token lexer.Token) Expr {
+	if ast.class == noClass {
+		ast.registerError(newSyntaxError("can't use this outside of a method", token.Span().Start))
+	}
+
+	return NewThis(token.Span())
+}
+
+// Super expression is built from the super keyword and the name of a superclass method after a dot.
+// It is only allowed within classes which have a superclass.
+func (ast *AST) super(token lexer.Token) Expr {
+	switch ast.class {
+	case noClass:
+		ast.registerError(newSyntaxError("can't use super outside of a method", token.Span().Start))
+	case plainClass:
+		ast.registerError(newSyntaxError("can't use super in a class without superclass", token.Span().Start))
+	}
+
+	ast.mustConsume(lexer.Dot)
+	method := ast.mustConsume(lexer.Identifier)
+
+	return NewSuper(method.Lexeme, ast.spanFrom(token))
+}
+
+// Anonymous function expression is built from the function keyword, a parenthesized list of parameters and its body.
+//
+// function (a, b) { ... }
+func (ast *AST) lambda(token lexer.Token) Expr {
+	params, body := ast.functionRest()
+	return NewLambda(params, body, false, ast.spanFrom(token))
+}
+
+// Unary expression is built from operator and its right operand, which binds tighter than any binary operator.
+func (ast *AST) unary(operator lexer.Token) Expr {
+	right := ast.parsePrecedence(unaryPrecedence)
+	return NewUnary(operator, right)
+}
+
+// Both group expressions and arrow functions start with a paren. Parameters of an arrow
+// function are followed by an arrow; otherwise, it is a group which holds a nested expression.
+func (ast *AST) groupOrArrowFunction(token lexer.Token) Expr {
+	if ast.isArrowFunction() {
+		return ast.arrowFunction(token)
+	}
+
+	expr := ast.expr()
+	ast.mustClose(token, lexer.RightParen)
+
+	return NewGroup(expr, ast.spanFrom(token))
+}
+
+// Arrow function is built from a parenthesized list of parameters, the arrow and its body.
+// Body can be either a block or a single expression, whose value is implicitly returned.
+// It assumes given opening paren is already consumed.
+//
+// (a, b) => a + b
+//
+// (a, b) => { return a + b; }
+func (ast *AST) arrowFunction(paren lexer.Token) Expr {
+	params := ast.parameterList(paren)
+	ast.mustConsume(lexer.Arrow)
+
+	if ast.match(lexer.LeftBrace) {
+		body := ast.functionBody(plainFunction)
+		return NewLambda(params, body, true, ast.spanFrom(paren))
+	}
+
+	enclosing, loops := ast.function, ast.loops
+	ast.function, ast.loops = plainFunction, nil
+	defer func() { ast.function, ast.loops = enclosing, loops }()
+
+	value := ast.expr()
+
+	return NewLambda(params, []Stmt{NewReturnStmt(value, value.Span())}, true, ast.spanFrom(paren))
+}
+
+// List literal is built from a comma separated list of expressions enclosed by brackets.
+func (ast *AST) listLiteral(token lexer.Token) Expr {
+	elements := ast.list(token, lexer.RightBracket)
+	return NewListLiteral(elements, ast.spanFrom(token))
+}
+
+// Map literal is built from a comma separated list of key and value expressions enclosed by braces.
+//
+// Within an expression, braces always start a map literal. Blocks are only parsed at
+// statement level, so a statement starting with a brace is a block.
+//
+// {"name": "gox", "port": 8080}
+func (ast *AST) mapLiteral(token lexer.Token) Expr {
+	keys := make([]Expr, 0)
+	values := make([]Expr, 0)
+
+	if !ast.check(lexer.RightBrace) {
+		for {
+			keys = append(keys, ast.expr())
+			ast.mustConsume(lexer.Colon)
+			values = append(values, ast.expr())
+
+			if !ast.match(lexer.Comma) {
+				break
+			}
+		}
+	}
+
+	ast.mustClose(token, lexer.RightBrace, lexer.Comma)
+
+	return NewMapLiteral(keys, values, ast.spanFrom(token))
+}
+
+// Match expression is built from the value to match and a comma separated list of arms enclosed by braces.
+// Each arm is built from a pattern, an optional guard and the expression to compute when it matches.
+//
+// match value { 1 | 2 => "small", [x, y] if x > y => x, _ => "other" }
+func (ast *AST) matchExpression(keyword lexer.Token) Expr {
+	subject := ast.expr()
+	brace := ast.mustConsume(lexer.LeftBrace)
+	arms := make([]MatchArm, 0)
+
+	for !ast.check(lexer.RightBrace) && !ast.isEnd() {
+		pattern := ast.matchPattern()
+		var guard Expr
+
+		if ast.match(lexer.If) {
+			guard = ast.guard()
+		} else if !ast.check(lexer.Arrow) {
+			panic(ast.unexpected(lexer.Pipe, lexer.If, lexer.Arrow))
+		}
+
+		ast.mustConsume(lexer.Arrow)
+		arms = append(arms, MatchArm{pattern, guard, ast.expr()})
+
+		if !ast.match(lexer.Comma) {
+			break
+		}
+	}
+
+	ast.mustClose(brace, lexer.RightBrace, lexer.Comma)
+	ast.checkMatchArms(subject, arms, keyword.Span().Start)
+
+	return NewMatch(subject, arms, ast.spanFrom(keyword))
+}
+
+// Parses the guard of a match arm. The arm arrow follows it, so it is located beforehand:
+// a parenthesized guard right before it must not be taken for the parameters of an arrow function.
+func (ast *AST) guard() Expr {
+	enclosing := ast.armArrow
+	ast.armArrow = ast.nextArrow()
+	defer func() { ast.armArrow = enclosing }()
+
+	return ast.expr()
+}
+
+// Finds the index of the next arrow which isn't nested within parens, brackets or braces.
+// It returns zero when the enclosing construct ends before any arrow.
+func (ast *AST) nextArrow() int {
+	depth := 0
+
+	for i := int(ast.current); i < len(ast.tokens); i++ {
+		switch ast.tokens[i].Kind {
+		case lexer.LeftParen, lexer.LeftBracket, lexer.LeftBrace:
+			depth++
+		case lexer.RightParen, lexer.RightBracket, lexer.RightBrace:
+			if depth--; depth < 0 {
+				return 0
+			}
+		case lexer.Arrow:
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return 0
+}
+
+// Looks for match arms which can't be reached, and for match expressions which can fail at runtime
+// because they don't have any catch-all arm or because no arm can match a literal subject.
+//
+// Unreachable arms are reported at their pattern, and failing matches at the given position.
+func (ast *AST) checkMatchArms(subject Expr, arms []MatchArm, position lexer.Position) {
+	exhaustive := false
+
+	for _, arm := range arms {
+		if exhaustive {
+			ast.registerWarning(newWarning(fmt.Sprintf("unreachable match arm (%s)", arm.pattern.String()), arm.pattern.Span().Start))
+			continue
+		}
+
+		exhaustive = arm.guard == nil && isIrrefutable(arm.pattern)
+	}
+
+	if exhaustive {
+		return
+	}
+
+	if literal, ok := subject.(Literal); ok {
+		for _, arm := range arms {
+			if canMatchLiteral(arm.pattern, literal.value) {
+				ast.registerWarning(newWarning("match is not exhaustive, add a wildcard (_) arm", position))
+				return
+			}
+		}
+
+		ast.registerWarning(newWarning(fmt.Sprintf("no match arm can match value %s", stringifyNested(literal.value)), position))
+		return
+	}
+
+	ast.registerWarning(newWarning("match is not exhaustive, add a wildcard (_) arm", position))
+}
+
+// Assignment expression is built from an assignment target, the equal sign and the value to assign.
+// Target is either a variable, a property or an index.
+//
+// Invalid targets are reported without stopping the parsing process, because parser state is still valid.
+func (ast *AST) assignment(target Expr, equal lexer.Token, operand precedence) Expr {
+	value := ast.parsePrecedence(operand)
+
+	switch t := target.(type) {
+	case Variable:
+		return NewAssign(t.name, value, t.span)
+	case Get:
+		if !t.optional {
+			return NewSet(t.object, t.name, value, t.nameSpan)
+		}
+	case Index:
+		if !t.optional {
+			return NewIndexSet(t.object, t.index, value, t.brackets)
+		}
+	}
+
+	ast.registerError(newSyntaxError(fmt.Sprintf("invalid assignment target (%s)", target.String()), equal.Span().Start))
+
+	return target
+}
+
+// Destructuring assignment is built from a list or map pattern, the equal sign and the value to destructure.
+//
+// [a, b] = [b, a]
+func (ast *AST) patternAssignment() Expr {
+	target := ast.pattern()
+	ast.mustConsume(lexer.Equal)
+	value := ast.parsePrecedence(assignmentPrecedence)
+
+	return NewDestructuringAssign(target, value)
+}
+
+// Conditional expression is built from a condition, and the branches to compute when it is truthy or falsy.
+// Then branch allows any expression, because it is delimited by the colon.
+//
+// condition ? thenBranch : elseBranch
+func (ast *AST) conditional(condition Expr, question lexer.Token, operand precedence) Expr {
+	thenBranch := ast.expr()
+	ast.mustConsume(lexer.Colon)
+	elseBranch := ast.parsePrecedence(operand)
+
+	return NewConditional(condition, thenBranch, elseBranch)
+}
+
+// Logical expression is built from left and right operands, and the and, or or ?? operators.
+func (ast *AST) logical(left Expr, operator lexer.Token, operand precedence) Expr {
+	right := ast.parsePrecedence(operand)
+	return NewLogical(left, operator, right)
+}
+
+// Binary expression is built from left and right operands, and an arithmetic, comparison or equality operator.
+func (ast *AST) binary(left Expr, operator lexer.Token, operand precedence) Expr {
+	right := ast.parsePrecedence(operand)
+	return NewBinary(left, operator, right)
+}
+
+// Call expression is built from a callee and a parenthesized list of arguments.
+func (ast *AST) call(callee Expr, paren lexer.Token) Expr {
+	return ast.finishCall(callee, paren, false)
+}
+
+func (ast *AST) finishCall(callee Expr, paren lexer.Token, optional bool) Expr {
+	args := ast.arguments(paren)
+	return NewCall(callee, args, optional, ast.previous().Span())
+}
+
+// Get expression is built from an object and the name of the property to access after a dot.
+func (ast *AST) get(object Expr, dot lexer.Token) Expr {
+	return ast.finishGet(object, false)
+}
+
+func (ast *AST) finishGet(object Expr, optional bool) Expr {
+	name := ast.mustConsume(lexer.Identifier)
+	return NewGet(object, name.Lexeme, optional, name.Span())
+}
+
+// Parses a bracketed index or slice over the given object.
+//
+// xs[i], xs[start:end], xs[start:], xs[:end], xs[:]
+func (ast *AST) indexOrSlice(object Expr, bracket lexer.Token) Expr {
+	return ast.finishIndexOrSlice(object, bracket, false)
+}
+
+// Optional chaining is built from an object, the ?. operator, and either the name of a property,
+// a parenthesized list of arguments or a bracketed index or slice. It results into null when the object is null.
+//
+// a?.b, f?.(x), xs?.[i]
+func (ast *AST) optionalChain(object Expr, questionDot lexer.Token) Expr {
+	switch {
+	case ast.match(lexer.LeftParen):
+		return ast.finishCall(object, ast.previous(), true)
+	case ast.match(lexer.LeftBracket):
+		return ast.finishIndexOrSlice(object, ast.previous(), true)
+	case ast.check(lexer.Identifier):
+		return ast.finishGet(object, true)
+	}
+
+	panic(ast.unexpected(lexer.Identifier, lexer.LeftParen, lexer.LeftBracket))
+}
+
+func (ast *AST) finishIndexOrSlice(object Expr, bracket lexer.Token, optional bool) Expr {
+	var start Expr
+
+	if !ast.check(lexer.Colon) {
+		start = ast.expr()
+	}
+
+	if !ast.match(lexer.Colon) {
+		ast.mustClose(bracket, lexer.RightBracket, lexer.Colon)
+		return NewIndex(object, start, optional, ast.spanFrom(bracket))
+	}
+
+	var end Expr
+
+	if !ast.check(lexer.RightBracket) {
+		end = ast.expr()
+	}
+
+	ast.mustClose(bracket, lexer.RightBracket)
+
+	return NewSlice(object, start, end, optional, ast.spanFrom(bracket))
+}
+
+// Parses a comma separated list of arguments, any of them may be spread or named.
+// Positional arguments can't follow named ones. It assumes given opening paren is already consumed.
+func (ast *AST) arguments(paren lexer.Token) []Expr {
+	args := make([]Expr, 0)
+	named := false
+
+	if !ast.check(lexer.RightParen) {
+		for {
+			if ast.check(lexer.Identifier) && ast.checkNext(lexer.Colon) {
+				name := ast.advance()
+				ast.advance()
+				args = append(args, NewNamedArgument(name.Lexeme, name.Span(), ast.expr()))
+				named = true
+			} else {
+				arg := ast.element()
+
+				if named {
+					ast.registerError(newSyntaxError("positional argument can't follow named arguments", arg.Span().Start))
+				}
+
+				args = append(args, arg)
+			}
+
+			if !ast.match(lexer.Comma) {
+				break
+			}
+		}
+	}
+
+	ast.mustClose(paren, lexer.RightParen, lexer.Comma)
+
+	return args
+}
+
+// Parses a comma separated list of expressions until the given closing token, any of them
+// may be spread. It assumes given opening token is already consumed.
+func (ast *AST) list(opening lexer.Token, closing lexer.TokenKind) []Expr {
+	exprs := make([]Expr, 0)
+
+	if !ast.check(closing) {
+		for {
+			exprs = append(exprs, ast.element())
+
+			if !ast.match(lexer.Comma) {
+				break
+			}
+		}
+	}
+
+	ast.mustClose(opening, closing, lexer.Comma)
+
+	return exprs
+}
+
+// Parses an element of a list or a positional argument, which is either an expression or a spread one.
+func (ast *AST) element() Expr {
+	if ast.match(lexer.Ellipsis) {
+		ellipsis := ast.previous()
+		expr := ast.expr()
+
+		return NewSpread(expr, ast.spanFrom(ellipsis))
+	}
+
+	return ast.expr()
+}
+
+// Determines if the parenthesized tokens after the already consumed paren are the parameters of an arrow
+// function, instead of a group expression. Both start alike, so it looks ahead for an arrow after the closing paren.
+// The arrow of the match arm whose guard is being parsed doesn't count, so parenthesized guards are groups.
+func (ast *AST) isArrowFunction() bool {
+	depth := 1
+
+	for i := int(ast.current); i < len(ast.tokens); i++ {
+		switch ast.tokens[i].Kind {
+		case lexer.LeftParen:
+			depth++
+		case lexer.RightParen:
+			depth--
+
+			if depth == 0 {
+				return i+1 < len(ast.tokens) && ast.tokens[i+1].Kind == lexer.Arrow && i+1 != ast.armArrow
+			}
+		case lexer.Eof:
+			return false
+		}
+	}
+
+	return false
+}
+
+// Describes the acceptable token kinds within error messages.
+//
+// 'a', 'b' or 'c'
+func describeKinds(kinds []lexer.TokenKind) string {
+	described := make([]string, len(kinds))
+
+	for i, kind := range kinds {
+		switch kind {
+		case lexer.Identifier:
+			described[i] = "identifier"
+		case lexer.String:
+			described[i] = "string"
+		case lexer.Number:
+			described[i] = "number"
+		case lexer.Eof:
+			described[i] = "end of file"
+		default:
+			described[i] = fmt.Sprintf("'%s'", lexer.TokenKindToLexemeMap[kind])
+		}
+	}
+
+	if len(described) == 1 {
+		return described[0]
+	}
+
+	return strings.Join(described[:len(described)-1], ", ") + " or " + described[len(described)-1]
+}
+
+// Describes a token within error messages.
+func describeToken(token lexer.Token) string {
+	if token.Kind == lexer.Eof {
+		return "end of file"
+	}
+
+	return fmt.Sprintf("'%s'", token.Lexeme)
+}
@@ -0,0 +1,551 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// Prints a syntax tree, or any of its nodes, as Gox source code.
+//
+// Unlike String, which shows the structure of the tree for debugging, printed source is valid Gox:
+// parsing it again yields an equivalent tree. Parens are only added where the precedence
+// or associativity of operators requires them; groups found in the tree are kept. Placeholders
+// of trees parsed from invalid source (BadExpr, BadStmt, BadPattern) can't be printed as valid source.
+//
+// Statements are printed one per line, and blocks are indented with tabs.
+func Format(node Node) string {
+	p := &printer{}
+
+	switch n := node.(type) {
+	case Program:
+		lines := make([]string, len(n.Statements))
+
+		for i, stmt := range n.Statements {
+			lines[i] = VisitStmt[string](p, stmt)
+		}
+
+		if len(lines) == 0 {
+			return ""
+		}
+
+		return strings.Join(lines, "\n") + "\n"
+	case Expr:
+		return VisitExpr[string](p, n)
+	case Stmt:
+		return VisitStmt[string](p, n)
+	case Pattern:
+		return p.pattern(n)
+	case MatchArm:
+		return p.arm(n)
+	case MapPatternEntry:
+		return p.entry(n)
+	}
+
+	panic(fmt.Sprintf("unexpected node type %T", node))
+}
+
+// Visitor which prints nodes as source code. It tracks the depth of the block being printed.
+type printer struct {
+	depth int
+}
+
+// Precedence of the operator at the top of an expression. Any expression which is not an operator
+// (literals, variables, groups, calls, ...) binds as tight as a call.
+func exprPrecedence(expr Expr) precedence {
+	switch e := expr.(type) {
+	case Binary:
+		return infixRules[e.operator.Kind].precedence
+	case Logical:
+		return infixRules[e.operator.Kind].precedence
+	case Conditional:
+		return conditionalPrecedence
+	case Assign, Set, IndexSet, DestructuringAssign:
+		return assignmentPrecedence
+	case Unary:
+		return unaryPrecedence
+	case Lambda:
+		// Body of arrow functions extends as far as possible, like the right operand of an assignment.
+		if e.arrow {
+			return assignmentPrecedence
+		}
+	case Literal:
+		// Negative numbers can only be written with a minus sign.
+		if number, ok := e.value.(float64); ok && number < 0 {
+			return unaryPrecedence
+		}
+	}
+
+	return callPrecedence
+}
+
+// Whether the printed expression ends with the body of an arrow function, which would extend
+// over anything printed after it.
+func endsWithArrowFunction(expr Expr) bool {
+	switch e := expr.(type) {
+	case Lambda:
+		return e.arrow
+	case Binary:
+		return endsWithArrowFunction(e.right)
+	case Logical:
+		return endsWithArrowFunction(e.right)
+	case Unary:
+		return endsWithArrowFunction(e.right)
+	case Conditional:
+		return endsWithArrowFunction(e.elseBranch)
+	case Assign:
+		return endsWithArrowFunction(e.value)
+	case Set:
+		return endsWithArrowFunction(e.value)
+	case IndexSet:
+		return endsWithArrowFunction(e.value)
+	case DestructuringAssign:
+		return endsWithArrowFunction(e.value)
+	}
+
+	return false
+}
+
+// Prints an operand which needs, at least, the given precedence, and which is followed by more
+// of the enclosing expression. It is grouped when it binds looser, or when its last arrow function
+// would take what follows as part of its body.
+func (p *printer) operand(expr Expr, min precedence) string {
+	printed := VisitExpr[string](p, expr)
+
+	if exprPrecedence(expr) < min || endsWithArrowFunction(expr) {
+		return "(" + printed + ")"
+	}
+
+	return printed
+}
+
+// Prints the last operand of an expression, which needs, at least, the given precedence. Arrow
+// functions are never grouped here, because their body already ends where the expression does.
+func (p *printer) lastOperand(expr Expr, min precedence) string {
+	printed := VisitExpr[string](p, expr)
+
+	if lambda, ok := expr.(Lambda); ok && lambda.arrow {
+		return printed
+	}
+
+	if exprPrecedence(expr) < min {
+		return "(" + printed + ")"
+	}
+
+	return printed
+}
+
+// Prints the left and right operands of an infix operator, following its precedence and associativity.
+func (p *printer) infix(left Expr, operator lexer.TokenKind, right Expr) (string, string) {
+	rule := infixRules[operator]
+	leftPrecedence := rule.precedence
+
+	if rule.associativity == rightAssociative {
+		leftPrecedence++
+	}
+
+	return p.operand(left, leftPrecedence), p.lastOperand(right, rule.operandPrecedence())
+}
+
+// Prints an expression which can't start with a brace, because it would be parsed as a block.
+func (p *printer) unbraced(expr Expr) string {
+	printed := VisitExpr[string](p, expr)
+
+	if strings.HasPrefix(printed, "{") {
+		return "(" + printed + ")"
+	}
+
+	return printed
+}
+
+func (p *printer) exprs(exprs []Expr) string {
+	printed := make([]string, len(exprs))
+
+	for i, expr := range exprs {
+		printed[i] = VisitExpr[string](p, expr)
+	}
+
+	return strings.Join(printed, ", ")
+}
+
+func (p *printer) VisitBinary(expr Binary) string {
+	left, right := p.infix(expr.left, expr.operator.Kind, expr.right)
+	return fmt.Sprintf("%s %s %s", left, expr.operator.Lexeme, right)
+}
+
+func (p *printer) VisitLogical(expr Logical) string {
+	left, right := p.infix(expr.left, expr.operator.Kind, expr.right)
+	return fmt.Sprintf("%s %s %s", left, expr.operator.Lexeme, right)
+}
+
+func (p *printer) VisitConditional(expr Conditional) string {
+	condition, elseBranch := p.infix(expr.condition, lexer.Question, expr.elseBranch)
+	return fmt.Sprintf("%s ? %s : %s", condition, VisitExpr[string](p, expr.thenBranch), elseBranch)
+}
+
+func (p *printer) VisitUnary(expr Unary) string {
+	return expr.operator.Lexeme + p.lastOperand(expr.right, unaryPrecedence)
+}
+
+func (p *printer) VisitCall(expr Call) string {
+	return fmt.Sprintf("%s%s(%s)", p.operand(expr.callee, callPrecedence), optionalMark(expr.optional), p.exprs(expr.args))
+}
+
+func (p *printer) VisitNamedArgument(expr NamedArgument) string {
+	return fmt.Sprintf("%s: %s", expr.name, VisitExpr[string](p, expr.value))
+}
+
+func (p *printer) VisitDestructuringAssign(expr DestructuringAssign) string {
+	return fmt.Sprintf("%s = %s", p.pattern(expr.target), p.lastOperand(expr.value, assignmentPrecedence))
+}
+
+func (p *printer) VisitMatch(expr Match) string {
+	arms := make([]string, len(expr.arms))
+
+	for i, arm := range expr.arms {
+		arms[i] = p.arm(arm)
+	}
+
+	return fmt.Sprintf("match %s { %s }", VisitExpr[string](p, expr.subject), strings.Join(arms, ", "))
+}
+
+func (p *printer) arm(arm MatchArm) string {
+	printed := p.pattern(arm.pattern)
+
+	if arm.guard != nil {
+		printed += " if " + VisitExpr[string](p, arm.guard)
+	}
+
+	return printed + " => " + VisitExpr[string](p, arm.body)
+}
+
+func (p *printer) VisitGet(expr Get) string {
+	if expr.optional {
+		return fmt.Sprintf("%s?.%s", p.operand(expr.object, callPrecedence), expr.name)
+	}
+
+	return fmt.Sprintf("%s.%s", p.operand(expr.object, callPrecedence), expr.name)
+}
+
+func (p *printer) VisitSet(expr Set) string {
+	return fmt.Sprintf("%s.%s = %s", p.operand(expr.object, callPrecedence), expr.name, p.lastOperand(expr.value, assignmentPrecedence))
+}
+
+func (p *printer) VisitThis(expr This) string {
+	return "this"
+}
+
+func (p *printer) VisitSuper(expr Super) string {
+	return "super." + expr.method
+}
+
+func (p *printer) VisitListLiteral(expr ListLiteral) string {
+	return fmt.Sprintf("[%s]", p.exprs(expr.elements))
+}
+
+func (p *printer) VisitSpread(expr Spread) string {
+	return "..." + VisitExpr[string](p, expr.expr)
+}
+
+func (p *printer) VisitMapLiteral(expr MapLiteral) string {
+	entries := make([]string, len(expr.keys))
+
+	for i := range expr.keys {
+		entries[i] = fmt.Sprintf("%s: %s", VisitExpr[string](p, expr.keys[i]), VisitExpr[string](p, expr.values[i]))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+func (p *printer) VisitIndex(expr Index) string {
+	return fmt.Sprintf("%s%s[%s]", p.operand(expr.object, callPrecedence), optionalMark(expr.optional), VisitExpr[string](p, expr.index))
+}
+
+func (p *printer) VisitIndexSet(expr IndexSet) string {
+	return fmt.Sprintf("%s[%s] = %s", p.operand(expr.object, callPrecedence), VisitExpr[string](p, expr.index), p.lastOperand(expr.value, assignmentPrecedence))
+}
+
+func (p *printer) VisitSlice(expr Slice) string {
+	var start, end string
+
+	if expr.start != nil {
+		start = VisitExpr[string](p, expr.start)
+	}
+
+	if expr.end != nil {
+		end = VisitExpr[string](p, expr.end)
+	}
+
+	return fmt.Sprintf("%s%s[%s:%s]", p.operand(expr.object, callPrecedence), optionalMark(expr.optional), start, end)
+}
+
+func (p *printer) VisitLambda(expr Lambda) string {
+	params := p.patterns(expr.params)
+
+	if !expr.arrow {
+		return fmt.Sprintf("function (%s) %s", params, p.block(expr.body))
+	}
+
+	if len(expr.body) == 1 {
+		if ret, ok := expr.body[0].(ReturnStmt); ok && ret.value != nil {
+			return fmt.Sprintf("(%s) => %s", params, p.unbraced(ret.value))
+		}
+	}
+
+	return fmt.Sprintf("(%s) => %s", params, p.block(expr.body))
+}
+
+func (p *printer) VisitGroup(expr Group) string {
+	return fmt.Sprintf("(%s)", VisitExpr[string](p, expr.expr))
+}
+
+func (p *printer) VisitLiteral(expr Literal) string {
+	switch value := expr.value.(type) {
+	case string:
+		return `"` + value + `"`
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return stringify(expr.value)
+}
+
+func (p *printer) VisitVariable(expr Variable) string {
+	return expr.name
+}
+
+func (p *printer) VisitAssign(expr Assign) string {
+	return fmt.Sprintf("%s = %s", expr.name, p.lastOperand(expr.value, assignmentPrecedence))
+}
+
+func (p *printer) VisitBadExpr(expr BadExpr) string {
+	return expr.String()
+}
+
+func (p *printer) VisitExpressionStmt(stmt ExpressionStmt) string {
+	// Only destructuring assignments can start with a brace at statement level; anything else is a block.
+	if _, ok := stmt.expr.(DestructuringAssign); ok {
+		return VisitExpr[string](p, stmt.expr) + ";"
+	}
+
+	return p.unbraced(stmt.expr) + ";"
+}
+
+func (p *printer) VisitPrintStmt(stmt PrintStmt) string {
+	return fmt.Sprintf("print %s;", VisitExpr[string](p, stmt.expr))
+}
+
+func (p *printer) VisitVarStmt(stmt VarStmt) string {
+	if stmt.initializer == nil {
+		return fmt.Sprintf("var %s;", p.pattern(stmt.target))
+	}
+
+	return fmt.Sprintf("var %s = %s;", p.pattern(stmt.target), VisitExpr[string](p, stmt.initializer))
+}
+
+func (p *printer) VisitBlockStmt(stmt BlockStmt) string {
+	return p.block(stmt.statements)
+}
+
+// Prints statements enclosed by braces, one per line and indented one level deeper than the block.
+func (p *printer) block(statements []Stmt) string {
+	if len(statements) == 0 {
+		return "{}"
+	}
+
+	p.depth++
+	lines := make([]string, len(statements))
+
+	for i, stmt := range statements {
+		lines[i] = strings.Repeat("\t", p.depth) + VisitStmt[string](p, stmt)
+	}
+
+	p.depth--
+
+	return fmt.Sprintf("{\n%s\n%s}", strings.Join(lines, "\n"), strings.Repeat("\t", p.depth))
+}
+
+func (p *printer) VisitIfStmt(stmt IfStmt) string {
+	condition := VisitExpr[string](p, stmt.condition)
+
+	if stmt.elseBranch == nil {
+		return fmt.Sprintf("if (%s) %s", condition, VisitStmt[string](p, stmt.thenBranch))
+	}
+
+	// An else always belongs to the nearest if, so a then branch ended by an if without else must be a block.
+	thenBranch := VisitStmt[string](p, stmt.thenBranch)
+
+	if hasDanglingIf(stmt.thenBranch) {
+		thenBranch = p.block([]Stmt{stmt.thenBranch})
+	}
+
+	return fmt.Sprintf("if (%s) %s else %s", condition, thenBranch, VisitStmt[string](p, stmt.elseBranch))
+}
+
+// Checks if the statement ends with an if statement without else branch, which would take any following else.
+func hasDanglingIf(stmt Stmt) bool {
+	switch s := stmt.(type) {
+	case IfStmt:
+		return s.elseBranch == nil || hasDanglingIf(s.elseBranch)
+	case WhileStmt:
+		return hasDanglingIf(s.body)
+	case ForStmt:
+		return hasDanglingIf(s.body)
+	}
+
+	return false
+}
+
+func (p *printer) VisitWhileStmt(stmt WhileStmt) string {
+	return fmt.Sprintf("%swhile (%s) %s", labelPrefix(stmt.label), VisitExpr[string](p, stmt.condition), VisitStmt[string](p, stmt.body))
+}
+
+func (p *printer) VisitForStmt(stmt ForStmt) string {
+	clauses := ";"
+
+	if stmt.initializer != nil {
+		clauses = VisitStmt[string](p, stmt.initializer)
+	}
+
+	if stmt.condition != nil {
+		clauses += " " + VisitExpr[string](p, stmt.condition)
+	}
+
+	clauses += ";"
+
+	if stmt.increment != nil {
+		clauses += " " + VisitExpr[string](p, stmt.increment)
+	}
+
+	return fmt.Sprintf("%sfor (%s) %s", labelPrefix(stmt.label), clauses, VisitStmt[string](p, stmt.body))
+}
+
+func (p *printer) VisitFunctionStmt(stmt FunctionStmt) string {
+	return fmt.Sprintf("function %s(%s) %s", stmt.name, p.patterns(stmt.params), p.block(stmt.body))
+}
+
+func (p *printer) VisitClassStmt(stmt ClassStmt) string {
+	header := "class " + stmt.name
+
+	if stmt.superclass != nil {
+		header += " < " + stmt.superclass.name
+	}
+
+	if len(stmt.methods) == 0 {
+		return header + " {}"
+	}
+
+	p.depth++
+	methods := make([]string, len(stmt.methods))
+
+	for i, method := range stmt.methods {
+		methods[i] = fmt.Sprintf("%s%s(%s) %s", strings.Repeat("\t", p.depth), method.name, p.patterns(method.params), p.block(method.body))
+	}
+
+	p.depth--
+
+	return fmt.Sprintf("%s {\n%s\n%s}", header, strings.Join(methods, "\n"), strings.Repeat("\t", p.depth))
+}
+
+func (p *printer) VisitReturnStmt(stmt ReturnStmt) string {
+	if stmt.value == nil {
+		return "return;"
+	}
+
+	return fmt.Sprintf("return %s;", VisitExpr[string](p, stmt.value))
+}
+
+func (p *printer) VisitBreakStmt(stmt BreakStmt) string {
+	return jumpString("break", stmt.label)
+}
+
+func (p *printer) VisitContinueStmt(stmt ContinueStmt) string {
+	return jumpString("continue", stmt.label)
+}
+
+func (p *printer) VisitBadStmt(stmt BadStmt) string {
+	return stmt.String()
+}
+
+func (p *printer) pattern(pattern Pattern) string {
+	switch pt := pattern.(type) {
+	case IdentifierPattern:
+		return pt.name
+	case RestPattern:
+		return "..." + pt.name
+	case DefaultPattern:
+		return fmt.Sprintf("%s = %s", p.pattern(pt.pattern), p.lastOperand(pt.value, assignmentPrecedence))
+	case ListPattern:
+		elements := make([]string, len(pt.elements), len(pt.elements)+1)
+
+		for i, element := range pt.elements {
+			elements[i] = p.pattern(element)
+		}
+
+		if pt.rest != nil {
+			elements = append(elements, "..."+p.pattern(pt.rest))
+		}
+
+		return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+	case MapPattern:
+		entries := make([]string, len(pt.entries))
+
+		for i, entry := range pt.entries {
+			entries[i] = p.entry(entry)
+		}
+
+		return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+	case WildcardPattern:
+		return "_"
+	case BadPattern:
+		return pt.String()
+	case LiteralPattern:
+		return VisitExpr[string](p, pt.literal)
+	case AlternativePattern:
+		alternatives := make([]string, len(pt.alternatives))
+
+		for i, alternative := range pt.alternatives {
+			alternatives[i] = p.pattern(alternative)
+		}
+
+		return strings.Join(alternatives, " | ")
+	case InstancePattern:
+		return fmt.Sprintf("%s %s", pt.class, p.pattern(pt.fields))
+	}
+
+	panic(fmt.Sprintf("unexpected pattern type %T", pattern))
+}
+
+func (p *printer) patterns(patterns []Pattern) string {
+	printed := make([]string, len(patterns))
+
+	for i, pattern := range patterns {
+		printed[i] = p.pattern(pattern)
+	}
+
+	return strings.Join(printed, ", ")
+}
+
+// Prints an entry of a map pattern. Keys which bind a variable of the same name are shortened.
+func (p *printer) entry(entry MapPatternEntry) string {
+	key := stringifyKey(entry.key)
+
+	if key != entry.key {
+		return fmt.Sprintf("%s: %s", key, p.pattern(entry.pattern))
+	}
+
+	switch pt := entry.pattern.(type) {
+	case IdentifierPattern:
+		if pt.name == entry.key {
+			return entry.key
+		}
+	case DefaultPattern:
+		if identifier, ok := pt.pattern.(IdentifierPattern); ok && identifier.name == entry.key {
+			return p.pattern(pt)
+		}
+	}
+
+	return fmt.Sprintf("%s: %s", entry.key, p.pattern(entry.pattern))
+}
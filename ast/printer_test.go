@@ -0,0 +1,102 @@
+package ast
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alfredoprograma/gox/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+// Describes the shape of a tree (node types, operators, names and values), leaving spans aside.
+func treeShape(node Node) []string {
+	shape := make([]string, 0)
+
+	Inspect(node, func(n Node) bool {
+		if n == nil {
+			shape = append(shape, "end")
+		} else {
+			shape = append(shape, fmt.Sprintf("%T %s", n, dotDetail(n)))
+		}
+
+		return true
+	})
+
+	return shape
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("should print source which parses into an equivalent tree", func(t *testing.T) {
+		sources := []string{
+			`var x = 1 + 2 * 3 - (4 - 5) / -x;`,
+			`print !(a == b) and (c or d) or e;`,
+			`a = b = c ? d : e ? f : g;`,
+			`print (a ? b : c) ? d : e;`,
+			`var f = (x, [y, z = 2]) => x + y;`,
+			`var g = function (a, {b, c: [d], "e-f": e, g = 1}) { return a; };`,
+			`print ((x) => x)(1) + f(2)(3).y[4][1:][:2][:];`,
+			`[a, b, ...rest] = [b, a];`,
+			`{name, port = 80} = config;`,
+			`({"a": 1}).a = 2;`,
+			`p.x = q.y = xs[0] = 3;`,
+			`print match x { 1 | -2 => "small", Point {x: 0, y} if y > 0 => y, [a, ...r] => r, {"k": v} => v, _ => null };`,
+			`print match x { y if (y > 0) => y, [z] if ((z)) and (z < 2) => z, _ => 0 };`,
+			`if (a) if (b) print 1; else print 2;`,
+			`if (a) { if (b) print 1; } else print 2;`,
+			`for (var i = 0; i < 10; i = i + 1) { print i; } for (;;) {} while (true) print "forever";`,
+			`outer: for (;;) { inner: while (a) { if (b) break outer; else continue inner; } continue; } while (c) break;`,
+			`function f(a, b) { var c; { print c; } return; } class A < B { init(x) { this.x = x; } m() { return super.m(); } } class C {}`,
+			`print "text" + 0.5 + 10 + true + false + null;`,
+			`print a?.b.c?.(1)?.[0]?.[1:] ?? (x ?? y) ?? z or w;`,
+			`function log(level, ...parts) { print [level, ...parts]; } log(...args, ...(a ?? b)); var f = (...xs) => xs;`,
+			`function connect(host, port = 5432, tls = true) {} connect("db", tls: a = b, port: x ? 1 : 2); var g = (a, [b] = [1]) => a;`,
+			`x = a + (y) => y;`,
+			`x = -(y) => y;`,
+			`x = a ? b : (c) => c;`,
+			`print (a + (y) => y) * 2 + (-(z) => z) + ((w) => w)(1);`,
+		}
+
+		for _, source := range sources {
+			program, errs := Parse(source)
+			assert.Empty(t, errs, source)
+
+			printed := Format(program)
+			reparsed, errs := Parse(printed)
+
+			assert.Empty(t, errs, printed)
+			assert.Equal(t, treeShape(program), treeShape(reparsed), printed)
+			assert.Equal(t, printed, Format(reparsed))
+		}
+	})
+
+	t.Run("should only add parens where precedence requires them", func(t *testing.T) {
+		number := func(lexeme string) Expr { return NewLiteral(lexeme, lexer.Number, lexer.Span{}) }
+		operator := func(kind lexer.TokenKind) lexer.Token { return lexer.MustCreateTokenFromKind(kind, 1) }
+
+		testCases := []struct {
+			expr     Expr
+			expected string
+		}{
+			{NewBinary(NewBinary(number("1"), operator(lexer.Minus), number("2")), operator(lexer.Minus), number("3")), "1 - 2 - 3"},
+			{NewBinary(number("1"), operator(lexer.Minus), NewBinary(number("2"), operator(lexer.Minus), number("3"))), "1 - (2 - 3)"},
+			{NewBinary(NewBinary(number("1"), operator(lexer.Plus), number("2")), operator(lexer.Star), number("3")), "(1 + 2) * 3"},
+			{NewBinary(number("1"), operator(lexer.Plus), NewBinary(number("2"), operator(lexer.Star), number("3"))), "1 + 2 * 3"},
+			{NewUnary(operator(lexer.Minus), NewBinary(number("2"), operator(lexer.Star), number("3"))), "-(2 * 3)"},
+			{NewLogical(NewLogical(NewVariable("a", lexer.Span{}), operator(lexer.Or), NewVariable("b", lexer.Span{})), operator(lexer.And), NewVariable("c", lexer.Span{})), "(a or b) and c"},
+			{NewConditional(NewConditional(NewVariable("a", lexer.Span{}), number("1"), number("2")), number("3"), number("4")), "(a ? 1 : 2) ? 3 : 4"},
+			{NewGet(NewAssign("a", number("1"), lexer.Span{}), "b", false, lexer.Span{}), "(a = 1).b"},
+			{NewCall(NewLambda(nil, []Stmt{NewReturnStmt(number("1"), lexer.Span{})}, true, lexer.Span{}), nil, false, lexer.Span{}), "(() => 1)()"},
+		}
+
+		for _, testCase := range testCases {
+			assert.Equal(t, testCase.expected, Format(testCase.expr))
+		}
+	})
+
+	t.Run("should indent blocks", func(t *testing.T) {
+		program, _ := Parse(`function f(x) { if (x) { return 1; } return function () { print x; }; }`)
+		expected := "function f(x) {\n\tif (x) {\n\t\treturn 1;\n\t}\n\treturn function () {\n\t\tprint x;\n\t};\n}\n"
+
+		assert.Equal(t, expected, Format(program))
+	})
+}
@@ -0,0 +1,720 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// A statement produces an effect instead of a value.
+type Stmt interface {
+	String() string                 // Exposes stringified version of the statement.
+	Span() lexer.Span               // Exposes the range of source the statement was parsed from.
+	Execute(env *Environment) error // Runs the statement within the given environment.
+}
+
+// A statement which evaluates an expression and discards its result.
+type ExpressionStmt struct {
+	expr Expr
+	span lexer.Span
+}
+
+func NewExpressionStmt(expr Expr, span lexer.Span) Stmt {
+	return ExpressionStmt{expr, span}
+}
+
+func (s ExpressionStmt) Expr() Expr {
+	return s.expr
+}
+
+func (s ExpressionStmt) String() string {
+	return fmt.Sprintf("%s;", s.expr.String())
+}
+
+func (s ExpressionStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s ExpressionStmt) Execute(env *Environment) error {
+	_, err := s.expr.Compute(env)
+	return err
+}
+
+// A statement which evaluates an expression and writes its stringified value.
+type PrintStmt struct {
+	expr Expr
+	span lexer.Span
+}
+
+func NewPrintStmt(expr Expr, span lexer.Span) Stmt {
+	return PrintStmt{expr, span}
+}
+
+func (s PrintStmt) Expr() Expr {
+	return s.expr
+}
+
+func (s PrintStmt) String() string {
+	return fmt.Sprintf("print %s;", s.expr.String())
+}
+
+func (s PrintStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s PrintStmt) Execute(env *Environment) error {
+	value, err := s.expr.Compute(env)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(env.out, stringify(value))
+	return err
+}
+
+// A statement which declares the variables named by its target pattern at current scope.
+//
+// Initializer is optional; when it is nil, target is bound to null.
+type VarStmt struct {
+	target      Pattern
+	initializer Expr
+	span        lexer.Span
+}
+
+func NewVarStmt(target Pattern, initializer Expr, span lexer.Span) Stmt {
+	return VarStmt{target, initializer, span}
+}
+
+func (s VarStmt) Target() Pattern {
+	return s.target
+}
+
+func (s VarStmt) Initializer() Expr {
+	return s.initializer
+}
+
+func (s VarStmt) String() string {
+	if s.initializer == nil {
+		return fmt.Sprintf("var %s;", s.target.String())
+	}
+
+	return fmt.Sprintf("var %s = %s;", s.target.String(), s.initializer.String())
+}
+
+func (s VarStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s VarStmt) Execute(env *Environment) error {
+	var value any
+
+	if s.initializer != nil {
+		computed, err := s.initializer.Compute(env)
+
+		if err != nil {
+			return err
+		}
+
+		value = computed
+	}
+
+	return s.target.bind(env, value, declareInto(env))
+}
+
+// A statement which groups a sequence of statements into a new nested scope.
+type BlockStmt struct {
+	statements []Stmt
+	span       lexer.Span
+}
+
+func NewBlockStmt(statements []Stmt, span lexer.Span) Stmt {
+	return BlockStmt{statements, span}
+}
+
+func (s BlockStmt) Statements() []Stmt {
+	return s.statements
+}
+
+func (s BlockStmt) String() string {
+	if len(s.statements) == 0 {
+		return "{}"
+	}
+
+	lines := make([]string, len(s.statements))
+
+	for i, stmt := range s.statements {
+		lines[i] = stmt.String()
+	}
+
+	return fmt.Sprintf("{ %s }", strings.Join(lines, " "))
+}
+
+func (s BlockStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s BlockStmt) Execute(env *Environment) error {
+	return executeBlock(s.statements, NewEnvironment(env))
+}
+
+// A statement which executes then branch when condition is truthy; otherwise executes else branch, if any.
+type IfStmt struct {
+	condition  Expr
+	thenBranch Stmt
+	elseBranch Stmt
+	span       lexer.Span
+}
+
+func NewIfStmt(condition Expr, thenBranch Stmt, elseBranch Stmt, span lexer.Span) Stmt {
+	return IfStmt{condition, thenBranch, elseBranch, span}
+}
+
+func (s IfStmt) Condition() Expr {
+	return s.condition
+}
+
+func (s IfStmt) ThenBranch() Stmt {
+	return s.thenBranch
+}
+
+func (s IfStmt) ElseBranch() Stmt {
+	return s.elseBranch
+}
+
+func (s IfStmt) String() string {
+	if s.elseBranch == nil {
+		return fmt.Sprintf("if (%s) %s", s.condition.String(), s.thenBranch.String())
+	}
+
+	return fmt.Sprintf("if (%s) %s else %s", s.condition.String(), s.thenBranch.String(), s.elseBranch.String())
+}
+
+func (s IfStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s IfStmt) Execute(env *Environment) error {
+	condition, err := s.condition.Compute(env)
+
+	if err != nil {
+		return err
+	}
+
+	if isTruthy(condition) {
+		return s.thenBranch.Execute(env)
+	}
+
+	if s.elseBranch != nil {
+		return s.elseBranch.Execute(env)
+	}
+
+	return nil
+}
+
+// A statement which repeats its body while condition is truthy.
+type WhileStmt struct {
+	condition Expr
+	body      Stmt
+	label     string // empty when loop is not labeled
+	span      lexer.Span
+}
+
+func NewWhileStmt(condition Expr, body Stmt, label string, span lexer.Span) Stmt {
+	return WhileStmt{condition, body, label, span}
+}
+
+func (s WhileStmt) Condition() Expr {
+	return s.condition
+}
+
+func (s WhileStmt) Body() Stmt {
+	return s.body
+}
+
+func (s WhileStmt) Label() string {
+	return s.label
+}
+
+func (s WhileStmt) String() string {
+	return fmt.Sprintf("%swhile (%s) %s", labelPrefix(s.label), s.condition.String(), s.body.String())
+}
+
+func (s WhileStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s WhileStmt) Execute(env *Environment) error {
+	for {
+		condition, err := s.condition.Compute(env)
+
+		if err != nil {
+			return err
+		}
+
+		if !isTruthy(condition) {
+			return nil
+		}
+
+		if done, err := executeLoopBody(s.body, env, s.label); done || err != nil {
+			return err
+		}
+	}
+}
+
+// A C-style loop statement. Initializer runs once within a new scope, then body and increment
+// are repeated while condition is truthy.
+//
+// Every clause is optional; a missing condition loops forever.
+type ForStmt struct {
+	initializer Stmt
+	condition   Expr
+	increment   Expr
+	body        Stmt
+	label       string // empty when loop is not labeled
+	span        lexer.Span
+}
+
+func NewForStmt(initializer Stmt, condition Expr, increment Expr, body Stmt, label string, span lexer.Span) Stmt {
+	return ForStmt{initializer, condition, increment, body, label, span}
+}
+
+func (s ForStmt) Initializer() Stmt {
+	return s.initializer
+}
+
+func (s ForStmt) Condition() Expr {
+	return s.condition
+}
+
+func (s ForStmt) Increment() Expr {
+	return s.increment
+}
+
+func (s ForStmt) Body() Stmt {
+	return s.body
+}
+
+func (s ForStmt) Label() string {
+	return s.label
+}
+
+func (s ForStmt) String() string {
+	initializer := ";"
+	condition := ""
+	increment := ""
+
+	if s.initializer != nil {
+		initializer = s.initializer.String()
+	}
+
+	if s.condition != nil {
+		condition = s.condition.String()
+	}
+
+	if s.increment != nil {
+		increment = s.increment.String()
+	}
+
+	return fmt.Sprintf("%sfor (%s %s; %s) %s", labelPrefix(s.label), initializer, condition, increment, s.body.String())
+}
+
+func (s ForStmt) Span() lexer.Span {
+	return s.span
+}
+
+// Continue statements skip the rest of the body, but increment is still computed.
+func (s ForStmt) Execute(env *Environment) error {
+	loopEnv := NewEnvironment(env)
+
+	if s.initializer != nil {
+		if err := s.initializer.Execute(loopEnv); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if s.condition != nil {
+			condition, err := s.condition.Compute(loopEnv)
+
+			if err != nil {
+				return err
+			}
+
+			if !isTruthy(condition) {
+				return nil
+			}
+		}
+
+		if done, err := executeLoopBody(s.body, loopEnv, s.label); done || err != nil {
+			return err
+		}
+
+		if s.increment != nil {
+			if _, err := s.increment.Compute(loopEnv); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Executes one iteration of the body of a loop with the given label. Loop is done when a break
+// statement targets it, while continue statements which target it just end the iteration.
+//
+// Unlabeled break and continue statements target the innermost loop. Signals which target
+// an outer loop are propagated, like any other error.
+func executeLoopBody(body Stmt, env *Environment, label string) (done bool, err error) {
+	err = body.Execute(env)
+
+	switch signal := err.(type) {
+	case breakSignal:
+		if signal.targets(label) {
+			return true, nil
+		}
+	case continueSignal:
+		if signal.targets(label) {
+			return false, nil
+		}
+	}
+
+	return false, err
+}
+
+// Stringifies the label of a loop, followed by a colon. Unlabeled loops have no prefix.
+func labelPrefix(label string) string {
+	if label == "" {
+		return ""
+	}
+
+	return label + ": "
+}
+
+// A statement which declares a named function at current scope.
+type FunctionStmt struct {
+	name   string
+	params []Pattern
+	body   []Stmt
+	span   lexer.Span
+}
+
+func NewFunctionStmt(name string, params []Pattern, body []Stmt, span lexer.Span) Stmt {
+	return FunctionStmt{name, params, body, span}
+}
+
+func (s FunctionStmt) Name() string {
+	return s.name
+}
+
+func (s FunctionStmt) Params() []Pattern {
+	return s.params
+}
+
+func (s FunctionStmt) Body() []Stmt {
+	return s.body
+}
+
+func (s FunctionStmt) String() string {
+	return fmt.Sprintf("function %s(%s) %s", s.name, joinPatterns(s.params), NewBlockStmt(s.body, s.span).String())
+}
+
+func (s FunctionStmt) Span() lexer.Span {
+	return s.span
+}
+
+// Binds a new function to its name. Current environment becomes the function closure.
+func (s FunctionStmt) Execute(env *Environment) error {
+	env.Define(s.name, newFunction(s.name, s.params, s.body, env, false))
+	return nil
+}
+
+// A statement which declares a class with its methods at current scope.
+// Superclass is optional; when it is nil, class doesn't inherit from any other class.
+type ClassStmt struct {
+	name       string
+	superclass *Variable
+	methods    []FunctionStmt
+	span       lexer.Span
+}
+
+func NewClassStmt(name string, superclass *Variable, methods []FunctionStmt, span lexer.Span) Stmt {
+	return ClassStmt{name, superclass, methods, span}
+}
+
+func (s ClassStmt) Name() string {
+	return s.name
+}
+
+func (s ClassStmt) Superclass() *Variable {
+	return s.superclass
+}
+
+func (s ClassStmt) Methods() []FunctionStmt {
+	return s.methods
+}
+
+func (s ClassStmt) String() string {
+	header := fmt.Sprintf("class %s", s.name)
+
+	if s.superclass != nil {
+		header = fmt.Sprintf("%s < %s", header, s.superclass.name)
+	}
+
+	if len(s.methods) == 0 {
+		return fmt.Sprintf("%s {}", header)
+	}
+
+	methods := make([]string, len(s.methods))
+
+	for i, method := range s.methods {
+		methods[i] = strings.TrimPrefix(method.String(), "function ")
+	}
+
+	return fmt.Sprintf("%s { %s }", header, strings.Join(methods, " "))
+}
+
+func (s ClassStmt) Span() lexer.Span {
+	return s.span
+}
+
+// Binds a new class to its name. Current environment becomes the closure of every method.
+//
+// When class has a superclass, methods closure is a nested environment where super is
+// bound to the superclass.
+func (s ClassStmt) Execute(env *Environment) error {
+	var superclass *Class
+
+	if s.superclass != nil {
+		value, err := s.superclass.Compute(env)
+
+		if err != nil {
+			return err
+		}
+
+		class, ok := value.(*Class)
+
+		if !ok {
+			return createASTErrorAt(fmt.Sprintf("class %s can't inherit from value of type %s", s.name, typeName(value)), s.superclass.span.Start)
+		}
+
+		superclass = class
+	}
+
+	closure := env
+
+	if superclass != nil {
+		closure = NewEnvironment(env)
+		closure.Define(SUPER_NAME, superclass)
+	}
+
+	methods := make(map[string]*Function, len(s.methods))
+
+	for _, method := range s.methods {
+		methods[method.name] = newFunction(method.name, method.params, method.body, closure, method.name == INITIALIZER_NAME)
+	}
+
+	env.Define(s.name, newClass(s.name, superclass, methods))
+	return nil
+}
+
+// A statement which finishes current function call. Value is optional; when it is nil, function returns null.
+type ReturnStmt struct {
+	value Expr
+	span  lexer.Span
+}
+
+func NewReturnStmt(value Expr, span lexer.Span) Stmt {
+	return ReturnStmt{value, span}
+}
+
+func (s ReturnStmt) Value() Expr {
+	return s.value
+}
+
+func (s ReturnStmt) String() string {
+	if s.value == nil {
+		return "return;"
+	}
+
+	return fmt.Sprintf("return %s;", s.value.String())
+}
+
+func (s ReturnStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s ReturnStmt) Execute(env *Environment) error {
+	var value any
+
+	if s.value != nil {
+		computed, err := s.value.Compute(env)
+
+		if err != nil {
+			return err
+		}
+
+		value = computed
+	}
+
+	return returnSignal{value}
+}
+
+// A statement which ends the innermost loop, or the enclosing loop with its label.
+type BreakStmt struct {
+	label string // empty when it targets the innermost loop
+	span  lexer.Span
+}
+
+func NewBreakStmt(label string, span lexer.Span) Stmt {
+	return BreakStmt{label, span}
+}
+
+func (s BreakStmt) Label() string {
+	return s.label
+}
+
+func (s BreakStmt) String() string {
+	return jumpString("break", s.label)
+}
+
+func (s BreakStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s BreakStmt) Execute(env *Environment) error {
+	return breakSignal{s.label}
+}
+
+// A statement which skips to the next iteration of the innermost loop, or the enclosing loop with its label.
+type ContinueStmt struct {
+	label string // empty when it targets the innermost loop
+	span  lexer.Span
+}
+
+func NewContinueStmt(label string, span lexer.Span) Stmt {
+	return ContinueStmt{label, span}
+}
+
+func (s ContinueStmt) Label() string {
+	return s.label
+}
+
+func (s ContinueStmt) String() string {
+	return jumpString("continue", s.label)
+}
+
+func (s ContinueStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s ContinueStmt) Execute(env *Environment) error {
+	return continueSignal{s.label}
+}
+
+// Stringifies a break or continue statement with its optional label.
+func jumpString(keyword string, label string) string {
+	if label == "" {
+		return keyword + ";"
+	}
+
+	return fmt.Sprintf("%s %s;", keyword, label)
+}
+
+// Unwinds the execution of a loop body up to the loop targeted by a break statement.
+//
+// Like returnSignal, it is propagated as an error through the statements, but it never escapes from its loop.
+type breakSignal struct {
+	label string
+}
+
+func (b breakSignal) targets(label string) bool {
+	return b.label == "" || b.label == label
+}
+
+func (b breakSignal) Error() string {
+	return fmt.Sprintf("%s: break statement outside of loop", AST_PREFIX)
+}
+
+// Unwinds the execution of a loop body up to the loop targeted by a continue statement, which starts its next iteration.
+type continueSignal struct {
+	label string
+}
+
+func (c continueSignal) targets(label string) bool {
+	return c.label == "" || c.label == label
+}
+
+func (c continueSignal) Error() string {
+	return fmt.Sprintf("%s: continue statement outside of loop", AST_PREFIX)
+}
+
+// Placeholder for a statement discarded by the parser after a syntax error, which lets it build a complete
+// tree from invalid source. Its span covers the discarded tokens.
+//
+// Trees with placeholders come along with syntax errors, so they are never executed.
+type BadStmt struct {
+	span lexer.Span
+}
+
+func NewBadStmt(span lexer.Span) Stmt {
+	return BadStmt{span}
+}
+
+func (s BadStmt) String() string {
+	return "<bad statement>"
+}
+
+func (s BadStmt) Span() lexer.Span {
+	return s.span
+}
+
+func (s BadStmt) Execute(env *Environment) error {
+	return createASTErrorAt("can't execute invalid statement", s.span.Start)
+}
+
+// Executes the statements in order within the given environment, stopping at the first error.
+func executeBlock(statements []Stmt, env *Environment) error {
+	for _, stmt := range statements {
+		if err := stmt.Execute(env); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Top level node of a Gox source. It holds the sequence of statements to execute,
+// and the warnings found while parsing them.
+type Program struct {
+	Statements []Stmt
+	Warnings   []error
+}
+
+func NewProgram(statements []Stmt, warnings []error) Program {
+	return Program{statements, warnings}
+}
+
+func (p Program) String() string {
+	lines := make([]string, len(p.Statements))
+
+	for i, stmt := range p.Statements {
+		lines[i] = stmt.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Spans from the first statement to the last one. It is zero when program is empty.
+func (p Program) Span() lexer.Span {
+	if len(p.Statements) == 0 {
+		return lexer.Span{}
+	}
+
+	return lexer.Join(p.Statements[0].Span(), p.Statements[len(p.Statements)-1].Span())
+}
+
+// Executes program statements in order within the given environment.
+// Execution stops at the first runtime error.
+func (p Program) Execute(env *Environment) error {
+	return executeBlock(p.Statements, env)
+}
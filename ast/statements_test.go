@@ -0,0 +1,710 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Parses and executes the given source, returning everything written by print statements.
+func execute(t *testing.T, source string) (string, error) {
+	t.Helper()
+
+	program, errs := Parse(source)
+
+	if len(errs) > 0 {
+		t.Fatalf("unexpected parsing errors: %v", errs)
+	}
+
+	var out bytes.Buffer
+	err := program.Execute(NewGlobalEnvironment(&out))
+
+	return out.String(), err
+}
+
+func TestStatementsStrings(t *testing.T) {
+	program, errs := Parse(`
+		var a; var b = 1 + 2; print b; b;
+		if (a) print a; else { print b; }
+		while (a != b) a = b;
+		for (;;) print a;
+		for (var i = 0; i < 1; i = i + 1) print i;
+	`)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, "var a;\nvar b = (1 + 2);\nprint b;\nb;\nif (a) print a; else { print b; }\nwhile ((a != b)) (a = b);\nfor (; ; ) print a;\nfor (var i = 0; (i < 1); (i = (i + 1))) print i;", program.String())
+}
+
+func TestStatementsExecution(t *testing.T) {
+	t.Run("should print computed values", func(t *testing.T) {
+		out, err := execute(t, `print 1 + 2; print "Hello"; print null; print true;`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3\nHello\nnull\ntrue\n", out)
+	})
+
+	t.Run("should declare and read variables", func(t *testing.T) {
+		out, err := execute(t, "var a = 10; var b; print a * 2; print b;")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "20\nnull\n", out)
+	})
+
+	t.Run("should shadow variables within nested blocks", func(t *testing.T) {
+		out, err := execute(t, `
+			var a = "global";
+			{
+				var a = "outer";
+				{
+					var a = "inner";
+					print a;
+				}
+				print a;
+			}
+			print a;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "inner\nouter\nglobal\n", out)
+	})
+
+	t.Run("should assign values through enclosing scopes", func(t *testing.T) {
+		out, err := execute(t, "var a = 1; var b; { a = b = a + 1; } print a; print b;")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n2\n", out)
+	})
+
+	t.Run("should fail assigning undeclared variables", func(t *testing.T) {
+		_, err := execute(t, "{ var a = 1; } a = 2;")
+
+		assert.EqualError(t, err, "[AST]: cannot assign to undeclared variable 'a' at 1:16")
+	})
+
+	t.Run("should locate runtime errors at the operator", func(t *testing.T) {
+		_, err := execute(t, "var a = 1;\nprint a +\n  \"b\";")
+
+		assert.EqualError(t, err, "[AST]: unrecognized value types number and string for binary operation at 2:9")
+	})
+
+	t.Run("should locate errors raised by natives at the call", func(t *testing.T) {
+		_, err := execute(t, "print len(1);")
+
+		assert.EqualError(t, err, "[AST]: len expects a list, map or string, got value of type number at 1:12")
+	})
+
+	t.Run("should execute branches based on truthiness", func(t *testing.T) {
+		out, err := execute(t, `
+			if (0) print "zero is truthy"; else print "zero is falsy";
+			if (null) print "null is truthy"; else print "null is falsy";
+			if ("" and false) print "unreachable";
+			if (false or "fallback") print "or returns truthy operand";
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "zero is truthy\nnull is falsy\nor returns truthy operand\n", out)
+	})
+
+	t.Run("should bind dangling else to the nearest if", func(t *testing.T) {
+		out, err := execute(t, `if (true) if (false) print "inner"; else print "nearest";`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "nearest\n", out)
+	})
+
+	t.Run("should repeat while loops", func(t *testing.T) {
+		out, err := execute(t, "var i = 0; while (i < 3) { print i; i = i + 1; }")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0\n1\n2\n", out)
+	})
+
+	t.Run("should repeat for loops scoping its initializer", func(t *testing.T) {
+		out, err := execute(t, `
+			var i = "outer";
+			for (var i = 0; i < 3; i = i + 1) print i;
+			print i;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0\n1\n2\nouter\n", out)
+	})
+
+	t.Run("should call functions and return values", func(t *testing.T) {
+		out, err := execute(t, `
+			function add(a, b) { return a + b; }
+			function noop() { return; }
+			print add(1, 2);
+			print noop();
+			print add;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3\nnull\n<function add>\n", out)
+	})
+
+	t.Run("should return from within nested loops", func(t *testing.T) {
+		out, err := execute(t, `
+			function firstAbove(limit) {
+				for (var i = 0; ; i = i + 1) {
+					while (true) {
+						if (i > limit) return i;
+						i = i + 1;
+					}
+				}
+			}
+			print firstAbove(3);
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "4\n", out)
+	})
+
+	t.Run("should capture defining environment on closures", func(t *testing.T) {
+		out, err := execute(t, `
+			function counter() {
+				var count = 0;
+				function increment() {
+					count = count + 1;
+					return count;
+				}
+				return increment;
+			}
+			var next = counter();
+			next();
+			print next();
+			print counter()();
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n1\n", out)
+	})
+
+	t.Run("should use anonymous functions as first class values", func(t *testing.T) {
+		out, err := execute(t, `
+			function apply(f, value) { return f(value); }
+			var factor = 3;
+			var triple = function (x) { return x * factor; };
+			print apply(triple, 2);
+			print apply((x) => x * 2, 5);
+			print apply((x) => { return (x + 1) * 2; }, 1);
+			print (() => (a, b) => a - b)()(10, 4);
+			print triple;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "6\n10\n4\n6\n<anonymous function>\n", out)
+	})
+
+	t.Run("should create class instances with fields and methods", func(t *testing.T) {
+		out, err := execute(t, `
+			class User {
+				init(name) {
+					this.name = name;
+					this.visits = 0;
+				}
+
+				visit() {
+					this.visits = this.visits + 1;
+					return this;
+				}
+
+				greet() {
+					return "Hello, " + this.name;
+				}
+			}
+
+			var user = User("Gox");
+			user.visit().visit();
+			print user.visits;
+			print user.greet();
+			print User;
+			print user;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\nHello, Gox\n<class User>\n<User instance>\n", out)
+	})
+
+	t.Run("should keep bound methods as first class values", func(t *testing.T) {
+		out, err := execute(t, `
+			class Counter {
+				init() { this.count = 0; }
+				increment() { this.count = this.count + 1; return this.count; }
+			}
+
+			var counter = Counter();
+			var increment = counter.increment;
+			increment();
+			print increment();
+			print counter.init().count;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n0\n", out)
+	})
+
+	t.Run("should inherit and override superclass methods", func(t *testing.T) {
+		out, err := execute(t, `
+			class User {
+				init(name) { this.name = name; }
+				describe() { return "user " + this.name; }
+				kind() { return "regular"; }
+			}
+
+			class Admin < User {
+				init(name, level) {
+					super.init(name);
+					this.level = level;
+				}
+
+				describe() { return "admin " + super.describe(); }
+			}
+
+			class Root < Admin {
+				kind() { return "root"; }
+			}
+
+			var root = Root("gox", 1);
+			print root.describe();
+			print root.kind();
+			print Admin("ada", 2).kind();
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admin user gox\nroot\nregular\n", out)
+	})
+
+	t.Run("should fail inheriting from non classes", func(t *testing.T) {
+		_, err := execute(t, "var User = 1; class Admin < User {}")
+
+		assert.EqualError(t, err, "[AST]: class Admin can't inherit from value of type number at 1:29")
+	})
+
+	t.Run("should fail accessing undefined properties", func(t *testing.T) {
+		_, err := execute(t, "class Empty {} Empty().missing;")
+
+		assert.EqualError(t, err, "[AST]: undefined property 'missing' on <Empty instance> at 1:24")
+	})
+
+	t.Run("should fail accessing properties of non instances", func(t *testing.T) {
+		_, err := execute(t, "var a = 1; a.b = 2;")
+
+		assert.EqualError(t, err, "[AST]: can't write property 'b' into value of type number at 1:14")
+	})
+
+	t.Run("should read, write and slice lists", func(t *testing.T) {
+		out, err := execute(t, `
+			var xs = [1, "two", [3]];
+			print xs;
+			print xs[0] + xs[2][0];
+			print xs[-1];
+			xs[1] = 2;
+			xs[-1] = xs[-1][0];
+			print xs;
+			print xs[1:3];
+			print xs[:-1];
+			print xs[-10:10];
+			print xs[2:1];
+			print len(xs) + len("gox");
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[1, \"two\", [3]]\n4\n[3]\n[1, 2, 3]\n[2, 3]\n[1, 2]\n[1, 2, 3]\n[]\n6\n", out)
+	})
+
+	t.Run("should share lists by reference", func(t *testing.T) {
+		out, err := execute(t, "var xs = [1]; var ys = xs; ys[0] = 2; print xs; print xs == ys; print xs == [2];")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[2]\ntrue\nfalse\n", out)
+	})
+
+	t.Run("should fail indexing out of bounds", func(t *testing.T) {
+		_, err := execute(t, "var xs = [1, 2];\nxs[-3];")
+
+		assert.EqualError(t, err, "[AST]: index -3 out of bounds for list of length 2 at 2:3")
+	})
+
+	t.Run("should fail indexing with non integer numbers", func(t *testing.T) {
+		_, err := execute(t, "[1, 2][0.5] = 1;")
+
+		assert.EqualError(t, err, "[AST]: index must be an integer number, got 0.5 at 1:7")
+	})
+
+	t.Run("should read, write and delete map entries in insertion order", func(t *testing.T) {
+		out, err := execute(t, `
+			var config = {"name": "x", "port": 8080, 1: true, false: null};
+			print config;
+			print config["name"] + "!";
+			config["port"] = config["port"] + 1;
+			config["tls"] = true;
+			print has(config, "tls");
+			print delete(config, "name");
+			print delete(config, "name");
+			print has(config, "name");
+			print keys(config);
+			print len(config);
+			print config;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			`{"name": "x", "port": 8080, 1: true, false: null}`,
+			"x!",
+			"true",
+			"true",
+			"false",
+			"false",
+			`["port", 1, false, "tls"]`,
+			"4",
+			`{"port": 8081, 1: true, false: null, "tls": true}`,
+		}, "\n")+"\n", out)
+	})
+
+	t.Run("should parse statements starting with braces as blocks", func(t *testing.T) {
+		out, err := execute(t, `{ print "block"; } print ({}); print {"nested": {}};`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "block\n{}\n{\"nested\": {}}\n", out)
+	})
+
+	t.Run("should fail reading missing map keys", func(t *testing.T) {
+		_, err := execute(t, "var m = {\"a\": 1};\nm[\"b\"];")
+
+		assert.EqualError(t, err, `[AST]: undefined key "b" at 2:2`)
+	})
+
+	t.Run("should fail using non hashable map keys", func(t *testing.T) {
+		_, err := execute(t, `var m = {[]: 1};`)
+
+		assert.EqualError(t, err, "[AST]: value of type list can't be used as map key at 1:10")
+	})
+
+	t.Run("should destructure lists and maps into declarations", func(t *testing.T) {
+		out, err := execute(t, `
+			var items = [1, 2, 3, 4];
+			var [first, second, ...rest] = items;
+			print [first, second, rest];
+			var [a, [b, c], d = "default", ...empty] = [1, [2, 3]];
+			print [a, b, c, d, empty];
+			var config = {"host": "localhost", "server": {"name": "gox"}, "content-type": "json"};
+			var {host, port = 8080, server: {name}, "content-type": contentType} = config;
+			print [host, port, name, contentType];
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[1, 2, [3, 4]]\n[1, 2, 3, \"default\", []]\n[\"localhost\", 8080, \"gox\", \"json\"]\n", out)
+	})
+
+	t.Run("should destructure values into assignments and parameters", func(t *testing.T) {
+		out, err := execute(t, `
+			var a = 1;
+			var b = 2;
+			[a, b] = [b, a];
+			print [a, b];
+			var host;
+			{host} = {"host": "gox.dev"};
+			print host;
+
+			class Point { init(x, y) { this.x = x; this.y = y; } }
+			function sum([x, y], {x: z}) { return x + y + z; }
+			print sum([1, 2], Point(3, 4));
+			print (([first]) => first)(["arrow"]);
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[2, 1]\ngox.dev\n6\narrow\n", out)
+	})
+
+	t.Run("should fail destructuring values with wrong shape", func(t *testing.T) {
+		cases := map[string]string{
+			"var [a, b] = [1];":           "[AST]: expected list of 2 elements, got 1 at 1:5",
+			"var [a, b = 1] = [1, 2, 3];": "[AST]: expected list of 1 to 2 elements, got 3 at 1:5",
+			"var [a, ...b] = [];":         "[AST]: expected list of at least 1 elements, got 0 at 1:5",
+			"var [a] = {};":               "[AST]: can't destructure value of type map as list at 1:5",
+			"var {a} = {\"b\": 1};":       "[AST]: missing key \"a\" at 1:6",
+			"var {a} = 1;":                "[AST]: can't destructure value of type number as map at 1:5",
+		}
+
+		for source, expected := range cases {
+			_, err := execute(t, source)
+
+			assert.EqualError(t, err, expected, source)
+		}
+	})
+
+	t.Run("should compute the first matching arm", func(t *testing.T) {
+		out, err := execute(t, `
+			class User { init(name) { this.name = name; } }
+			class Admin < User {}
+
+			function describe(value) {
+				return match value {
+					1 | 2 => "small",
+					-1 => "negative",
+					"gox" | true | null => "keyword",
+					[x, y] if x > y => "descending pair",
+					[x, ...rest] => rest,
+					{kind: "user", name} => "user " + name,
+					Admin {name} => "admin " + name,
+					User {} => "user instance",
+					n if n > 100 => "big",
+					_ => "other"
+				};
+			}
+
+			var values = [2, -1, null, [2, 1], [1, 2], {"kind": "user", "name": "ada"}, Admin("root"), User("gox"), 101, 50];
+
+			for (var i = 0; i < len(values); i = i + 1) {
+				print describe(values[i]);
+			}
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"small",
+			"negative",
+			"keyword",
+			"descending pair",
+			"[2]",
+			"user ada",
+			"admin root",
+			"user instance",
+			"big",
+			"other",
+		}, "\n")+"\n", out)
+	})
+
+	t.Run("should accept parenthesized match arm guards", func(t *testing.T) {
+		out, err := execute(t, `
+			print match 3 { y if (y > 0) => 1, _ => 2 };
+			print match -3 { y if (y > 0) or ((x) => x)(false) => 1, y if (((x) => x > 0)(-y)) => y, _ => 0 };
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n-3\n", out)
+	})
+
+	t.Run("should short-circuit optional chains to null", func(t *testing.T) {
+		out, err := execute(t, `
+			class Config { init(db) { this.db = db; } }
+			var config = Config(null);
+			var hosts = null;
+			var connect = null;
+			print config.db?.host.name;
+			print hosts?.[0];
+			print hosts?.[1:];
+			print connect?.("db");
+			config.db = {"host": "localhost"};
+			print config?.db["host"];
+			print [1, 2, 3]?.[1:];
+			print (function (x) { return x; })?.(1);
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "null\nnull\nnull\nnull\nlocalhost\n[2, 3]\n1\n", out)
+	})
+
+	t.Run("should not short-circuit past groups", func(t *testing.T) {
+		_, err := execute(t, "var a = null; print (a?.b).c;")
+
+		assert.EqualError(t, err, "[AST]: can't read property 'c' from value of type null at 1:28")
+	})
+
+	t.Run("should only fall back on null when coalescing", func(t *testing.T) {
+		out, err := execute(t, `
+			var settings = null;
+			print settings ?? "default";
+			print false ?? "default";
+			print 0 ?? "default";
+			print "" ?? "default";
+			print null ?? null ?? 3;
+			print false or "fallback";
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "default\nfalse\n0\n\n3\nfallback\n", out)
+	})
+
+	t.Run("should not compute coalesced values when not needed", func(t *testing.T) {
+		out, err := execute(t, `var calls = 0; function f() { calls = calls + 1; return 1; } print 2 ?? f(); print calls;`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n0\n", out)
+	})
+
+	t.Run("should collect remaining arguments into rest parameter", func(t *testing.T) {
+		out, err := execute(t, `
+			function log(level, ...parts) { print level; print parts; }
+			log("info");
+			log("warn", 1, 2);
+			var count = (...xs) => len(xs);
+			print count(1, 2, 3);
+			class Point { init(...coords) { this.coords = coords; } }
+			print Point(1, 2).coords;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "info\n[]\nwarn\n[1, 2]\n3\n[1, 2]\n", out)
+	})
+
+	t.Run("should spread lists into arguments and list literals", func(t *testing.T) {
+		out, err := execute(t, `
+			function add(a, b, c) { return a + b + c; }
+			var xs = [1, 2];
+			print add(...xs, 3);
+			print add(0, ...xs);
+			print [...xs, ...[], 3, ...xs];
+			function last(...items) { return items[-1]; }
+			print last(...xs, ...[5]);
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "6\n3\n[1, 2, 3, 1, 2]\n5\n", out)
+	})
+
+	t.Run("should fail calling variadic functions with too few arguments", func(t *testing.T) {
+		_, err := execute(t, "function f(a, b, ...c) {} f(...[1]);")
+
+		assert.EqualError(t, err, "[AST]: <function f> expected at least 2 arguments but got 1 at 1:35")
+	})
+
+	t.Run("should fail spreading non iterable values", func(t *testing.T) {
+		_, err := execute(t, `print [...{"a": 1}];`)
+
+		assert.EqualError(t, err, "[AST]: can't spread value of type map, it is not iterable at 1:8")
+	})
+
+	t.Run("should compute default parameters at call time", func(t *testing.T) {
+		out, err := execute(t, `
+			var calls = 0;
+			function next() { calls = calls + 1; return calls; }
+			function connect(host, port = 5432, tls = port != 80, id = next()) { print [host, port, tls, id]; }
+			connect("db");
+			connect("db", 80);
+			connect("db", 80, null, 0);
+			print calls;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[\"db\", 5432, true, 1]\n[\"db\", 80, false, 2]\n[\"db\", 80, null, 0]\n2\n", out)
+	})
+
+	t.Run("should pass arguments by name", func(t *testing.T) {
+		out, err := execute(t, `
+			function connect(host, port = 5432, tls = true, ...rest) { print [host, port, tls, rest]; }
+			connect("db", tls: false);
+			connect(tls: false, host: "db");
+			connect("db", 1, 2, 3, 4);
+			class Server { init(host, port = 80) { this.port = port; } }
+			print Server(port: 8080, host: "web").port;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[\"db\", 5432, false, []]\n[\"db\", 5432, false, []]\n[\"db\", 1, 2, [3, 4]]\n8080\n", out)
+	})
+
+	t.Run("should fail passing unknown or duplicated named arguments", func(t *testing.T) {
+		testCases := []struct {
+			source   string
+			expected string
+		}{
+			{`function f(a, b = 1) {} f(1, c: 2);`, "[AST]: <function f> has no parameter named 'c' at 1:30"},
+			{`function f(a, b = 1) {} f(1, a: 2);`, "[AST]: <function f> got multiple values for parameter 'a' at 1:30"},
+			{`function f(a, b = 1) {} f(b: 1, b: 2);`, "[AST]: <function f> got multiple values for parameter 'b' at 1:33"},
+			{`function f(a, b, c = 1) {} f(1, c: 2);`, "[AST]: <function f> expected an argument for parameter 'b' at 1:37"},
+			{`function f(a, b = 1) {} f(1, 2, 3);`, "[AST]: <function f> expected 1 to 2 arguments but got 3 at 1:34"},
+			{`function f([a], b) {} f(b: 1);`, "[AST]: <function f> expected 2 arguments but got 1 at 1:29"},
+			{`len(value: "text");`, "[AST]: <native function len> has no parameter named 'value' at 1:5"},
+		}
+
+		for _, testCase := range testCases {
+			_, err := execute(t, testCase.source)
+
+			assert.EqualError(t, err, testCase.expected, testCase.source)
+		}
+	})
+
+	t.Run("should break and continue the innermost loop", func(t *testing.T) {
+		out, err := execute(t, `
+			for (var i = 0; i < 10; i = i + 1) {
+				if (i == 1) continue;
+				if (i == 4) break;
+				{ print i; }
+			}
+			var n = 0;
+			while (true) {
+				n = n + 1;
+				if (n < 3) continue;
+				break;
+			}
+			print n;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0\n2\n3\n3\n", out)
+	})
+
+	t.Run("should break and continue labeled loops", func(t *testing.T) {
+		out, err := execute(t, `
+			outer: for (var i = 0; i < 3; i = i + 1) {
+				for (var j = 0; j < 3; j = j + 1) {
+					if (j == 1) continue outer;
+					if (i == 2) break outer;
+					print [i, j];
+				}
+			}
+			var found = null;
+			search: while (found == null) {
+				for (var k = 0; ; k = k + 1) {
+					if (k * k > 10) { found = k; break search; }
+				}
+			}
+			print found;
+		`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[0, 0]\n[1, 0]\n4\n", out)
+	})
+
+	t.Run("should return from functions within loops", func(t *testing.T) {
+		out, err := execute(t, `function first(xs) { for (var i = 0; ; i = i + 1) { while (true) { return xs[i]; } } } print first([7]);`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "7\n", out)
+	})
+
+	t.Run("should scope match arm bindings", func(t *testing.T) {
+		out, err := execute(t, `var x = "outer"; print match [1] { [x] => x }; print x;`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1\nouter\n", out)
+	})
+
+	t.Run("should fail when no match arm matches", func(t *testing.T) {
+		_, err := execute(t, "var value = 3;\nmatch value { 1 => 1, 2 => 2 };")
+
+		assert.EqualError(t, err, "[AST]: no match arm for value 3 at 2:1")
+	})
+
+	t.Run("should fail calling with wrong arguments count", func(t *testing.T) {
+		_, err := execute(t, "function f(a) {} f(1, 2);")
+
+		assert.EqualError(t, err, "[AST]: <function f> expected 1 arguments but got 2 at 1:24")
+	})
+
+	t.Run("should fail calling non callable values", func(t *testing.T) {
+		_, err := execute(t, `"text"();`)
+
+		assert.EqualError(t, err, "[AST]: value of type string is not callable at 1:8")
+	})
+
+	t.Run("should fail reading undefined variables", func(t *testing.T) {
+		_, err := execute(t, "print missing;")
+
+		assert.EqualError(t, err, "[AST]: undefined variable 'missing' at 1:7")
+	})
+}
@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Builds the representation of a runtime value as shown by print statements.
+func stringify(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Builds the representation of a runtime value nested into a collection.
+// Unlike top level values, strings are quoted to make them distinguishable.
+func stringifyNested(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+
+	return stringify(value)
+}
+
+// Determines if a runtime value is considered true within a condition.
+// Only null and false are falsy; any other value is truthy.
+func isTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// Determines if two runtime values are equal. Values of different types are never equal.
+func isEqual(left any, right any) bool {
+	return left == right
+}
+
+// Returns the Gox name for the type of a runtime value, used to build error messages.
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case *List:
+		return "list"
+	case *Map:
+		return "map"
+	case *Class:
+		return "class"
+	case *Instance:
+		return "instance"
+	case Callable:
+		return "function"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
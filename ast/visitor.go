@@ -0,0 +1,310 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/alfredoprograma/gox/lexer"
+)
+
+// Any node of a syntax tree: expressions, statements, patterns, match arms, map pattern entries and programs.
+type Node interface {
+	String() string
+	Span() lexer.Span
+}
+
+// Computes a result of type R from each kind of expression.
+//
+// Passes over expressions (printers, checkers, compilers) implement it outside of node types,
+// and dispatch through VisitExpr.
+type ExprVisitor[R any] interface {
+	VisitBinary(expr Binary) R
+	VisitLogical(expr Logical) R
+	VisitConditional(expr Conditional) R
+	VisitUnary(expr Unary) R
+	VisitCall(expr Call) R
+	VisitNamedArgument(expr NamedArgument) R
+	VisitDestructuringAssign(expr DestructuringAssign) R
+	VisitMatch(expr Match) R
+	VisitGet(expr Get) R
+	VisitSet(expr Set) R
+	VisitThis(expr This) R
+	VisitSuper(expr Super) R
+	VisitListLiteral(expr ListLiteral) R
+	VisitSpread(expr Spread) R
+	VisitMapLiteral(expr MapLiteral) R
+	VisitIndex(expr Index) R
+	VisitIndexSet(expr IndexSet) R
+	VisitSlice(expr Slice) R
+	VisitLambda(expr Lambda) R
+	VisitGroup(expr Group) R
+	VisitLiteral(expr Literal) R
+	VisitVariable(expr Variable) R
+	VisitAssign(expr Assign) R
+	VisitBadExpr(expr BadExpr) R
+}
+
+// Computes a result of type R from each kind of statement. It is dispatched through VisitStmt.
+type StmtVisitor[R any] interface {
+	VisitExpressionStmt(stmt ExpressionStmt) R
+	VisitPrintStmt(stmt PrintStmt) R
+	VisitVarStmt(stmt VarStmt) R
+	VisitBlockStmt(stmt BlockStmt) R
+	VisitIfStmt(stmt IfStmt) R
+	VisitWhileStmt(stmt WhileStmt) R
+	VisitForStmt(stmt ForStmt) R
+	VisitFunctionStmt(stmt FunctionStmt) R
+	VisitClassStmt(stmt ClassStmt) R
+	VisitReturnStmt(stmt ReturnStmt) R
+	VisitBreakStmt(stmt BreakStmt) R
+	VisitContinueStmt(stmt ContinueStmt) R
+	VisitBadStmt(stmt BadStmt) R
+}
+
+// Computes a result of type R from each kind of expression and statement.
+type Visitor[R any] interface {
+	ExprVisitor[R]
+	StmtVisitor[R]
+}
+
+// Calls the method of the visitor which corresponds to the kind of the given expression.
+//
+// It panics on unknown expression kinds, which can only be defined within this package.
+func VisitExpr[R any](visitor ExprVisitor[R], expr Expr) R {
+	switch e := expr.(type) {
+	case Binary:
+		return visitor.VisitBinary(e)
+	case Logical:
+		return visitor.VisitLogical(e)
+	case Conditional:
+		return visitor.VisitConditional(e)
+	case Unary:
+		return visitor.VisitUnary(e)
+	case Call:
+		return visitor.VisitCall(e)
+	case NamedArgument:
+		return visitor.VisitNamedArgument(e)
+	case DestructuringAssign:
+		return visitor.VisitDestructuringAssign(e)
+	case Match:
+		return visitor.VisitMatch(e)
+	case Get:
+		return visitor.VisitGet(e)
+	case Set:
+		return visitor.VisitSet(e)
+	case This:
+		return visitor.VisitThis(e)
+	case Super:
+		return visitor.VisitSuper(e)
+	case ListLiteral:
+		return visitor.VisitListLiteral(e)
+	case Spread:
+		return visitor.VisitSpread(e)
+	case MapLiteral:
+		return visitor.VisitMapLiteral(e)
+	case Index:
+		return visitor.VisitIndex(e)
+	case IndexSet:
+		return visitor.VisitIndexSet(e)
+	case Slice:
+		return visitor.VisitSlice(e)
+	case Lambda:
+		return visitor.VisitLambda(e)
+	case Group:
+		return visitor.VisitGroup(e)
+	case Literal:
+		return visitor.VisitLiteral(e)
+	case Variable:
+		return visitor.VisitVariable(e)
+	case Assign:
+		return visitor.VisitAssign(e)
+	case BadExpr:
+		return visitor.VisitBadExpr(e)
+	}
+
+	panic(fmt.Sprintf("unexpected expression type %T", expr))
+}
+
+// Calls the method of the visitor which corresponds to the kind of the given statement.
+//
+// It panics on unknown statement kinds, which can only be defined within this package.
+func VisitStmt[R any](visitor StmtVisitor[R], stmt Stmt) R {
+	switch s := stmt.(type) {
+	case ExpressionStmt:
+		return visitor.VisitExpressionStmt(s)
+	case PrintStmt:
+		return visitor.VisitPrintStmt(s)
+	case VarStmt:
+		return visitor.VisitVarStmt(s)
+	case BlockStmt:
+		return visitor.VisitBlockStmt(s)
+	case IfStmt:
+		return visitor.VisitIfStmt(s)
+	case WhileStmt:
+		return visitor.VisitWhileStmt(s)
+	case ForStmt:
+		return visitor.VisitForStmt(s)
+	case FunctionStmt:
+		return visitor.VisitFunctionStmt(s)
+	case ClassStmt:
+		return visitor.VisitClassStmt(s)
+	case ReturnStmt:
+		return visitor.VisitReturnStmt(s)
+	case BreakStmt:
+		return visitor.VisitBreakStmt(s)
+	case ContinueStmt:
+		return visitor.VisitContinueStmt(s)
+	case BadStmt:
+		return visitor.VisitBadStmt(s)
+	}
+
+	panic(fmt.Sprintf("unexpected statement type %T", stmt))
+}
+
+// A Walker's Visit method is called by Walk for each node of the tree.
+//
+// If the returned walker is not nil, Walk visits each of the children of the node with it,
+// followed by a call of Visit(nil).
+type Walker interface {
+	Visit(node Node) (w Walker)
+}
+
+// Traverses a syntax tree in depth-first order. It starts by calling w.Visit(node); if the
+// returned walker is not nil, Walk is called recursively with it for each non-nil child of
+// the node, followed by a call of w.Visit(nil).
+func Walk(w Walker, node Node) {
+	if w = w.Visit(node); w == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case Program:
+		walkList(w, n.Statements)
+
+	// Expressions
+	case Binary:
+		walkNodes(w, n.left, n.right)
+	case Logical:
+		walkNodes(w, n.left, n.right)
+	case Conditional:
+		walkNodes(w, n.condition, n.thenBranch, n.elseBranch)
+	case Unary:
+		walkNodes(w, n.right)
+	case Call:
+		walkNodes(w, n.callee)
+		walkList(w, n.args)
+	case NamedArgument:
+		walkNodes(w, n.value)
+	case DestructuringAssign:
+		walkNodes(w, n.target, n.value)
+	case Match:
+		walkNodes(w, n.subject)
+		walkList(w, n.arms)
+	case MatchArm:
+		walkNodes(w, n.pattern, n.guard, n.body)
+	case Get:
+		walkNodes(w, n.object)
+	case Set:
+		walkNodes(w, n.object, n.value)
+	case ListLiteral:
+		walkList(w, n.elements)
+	case Spread:
+		walkNodes(w, n.expr)
+	case MapLiteral:
+		for i := range n.keys {
+			walkNodes(w, n.keys[i], n.values[i])
+		}
+	case Index:
+		walkNodes(w, n.object, n.index)
+	case IndexSet:
+		walkNodes(w, n.object, n.index, n.value)
+	case Slice:
+		walkNodes(w, n.object, n.start, n.end)
+	case Lambda:
+		walkList(w, n.params)
+		walkList(w, n.body)
+	case Group:
+		walkNodes(w, n.expr)
+	case Assign:
+		walkNodes(w, n.value)
+
+	// Statements
+	case ExpressionStmt:
+		walkNodes(w, n.expr)
+	case PrintStmt:
+		walkNodes(w, n.expr)
+	case VarStmt:
+		walkNodes(w, n.target, n.initializer)
+	case BlockStmt:
+		walkList(w, n.statements)
+	case IfStmt:
+		walkNodes(w, n.condition, n.thenBranch, n.elseBranch)
+	case WhileStmt:
+		walkNodes(w, n.condition, n.body)
+	case ForStmt:
+		walkNodes(w, n.initializer, n.condition, n.increment, n.body)
+	case FunctionStmt:
+		walkList(w, n.params)
+		walkList(w, n.body)
+	case ClassStmt:
+		if n.superclass != nil {
+			walkNodes(w, *n.superclass)
+		}
+
+		walkList(w, n.methods)
+	case ReturnStmt:
+		walkNodes(w, n.value)
+
+	// Patterns
+	case DefaultPattern:
+		walkNodes(w, n.pattern, n.value)
+	case ListPattern:
+		walkList(w, n.elements)
+		walkNodes(w, n.rest)
+	case MapPattern:
+		walkList(w, n.entries)
+	case MapPatternEntry:
+		walkNodes(w, n.pattern)
+	case LiteralPattern:
+		walkNodes(w, n.literal)
+	case AlternativePattern:
+		walkList(w, n.alternatives)
+	case InstancePattern:
+		walkNodes(w, n.fields)
+	}
+
+	w.Visit(nil)
+}
+
+// Walks each of the given nodes, skipping the nil ones (absent optional children).
+func walkNodes(w Walker, nodes ...Node) {
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+
+		Walk(w, node)
+	}
+}
+
+func walkList[N Node](w Walker, nodes []N) {
+	for _, node := range nodes {
+		walkNodes(w, node)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Walker {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Traverses a syntax tree in depth-first order. It starts by calling f(node); if it returns
+// true, Inspect is called recursively with f for each non-nil child of the node, followed by
+// a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,74 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Visitor which only knows arithmetic; the embedded interface stands for the other node kinds.
+type arithmeticVisitor struct {
+	ExprVisitor[float64]
+}
+
+func (v arithmeticVisitor) VisitBinary(expr Binary) float64 {
+	left, right := VisitExpr[float64](v, expr.Left()), VisitExpr[float64](v, expr.Right())
+	value, _ := computeNumberBinaryOperation(left, expr.Operator().Kind, right)
+
+	return value.(float64)
+}
+
+func (v arithmeticVisitor) VisitGroup(expr Group) float64 {
+	return VisitExpr[float64](v, expr.Expr())
+}
+
+func (v arithmeticVisitor) VisitLiteral(expr Literal) float64 {
+	return expr.Value().(float64)
+}
+
+func TestVisitor(t *testing.T) {
+	t.Run("should dispatch expressions to visitor methods", func(t *testing.T) {
+		program, _ := Parse("(1 + 2) * 4 - 3;")
+		expr := program.Statements[0].(ExpressionStmt).Expr()
+
+		assert.Equal(t, float64(9), VisitExpr[float64](arithmeticVisitor{}, expr))
+	})
+
+	t.Run("should inspect every node in depth-first order", func(t *testing.T) {
+		program, _ := Parse("var [a, b = 1] = xs; if (a) print b; else { f(a)[0]; }")
+		nodes := make([]string, 0)
+
+		Inspect(program, func(node Node) bool {
+			switch n := node.(type) {
+			case IdentifierPattern:
+				nodes = append(nodes, "pattern "+n.Name())
+			case Variable:
+				nodes = append(nodes, "variable "+n.Name())
+			case Literal:
+				nodes = append(nodes, "literal "+n.String())
+			}
+
+			return true
+		})
+
+		assert.Equal(t, "pattern a, pattern b, literal 1, variable xs, variable a, variable b, variable f, variable a, literal 0", strings.Join(nodes, ", "))
+	})
+
+	t.Run("should skip children of nodes when inspector returns false", func(t *testing.T) {
+		program, _ := Parse("function f(x) { return x; } f(1);")
+		calls := 0
+
+		Inspect(program, func(node Node) bool {
+			if _, ok := node.(Call); ok {
+				calls++
+			}
+
+			_, isFunction := node.(FunctionStmt)
+
+			return !isFunction
+		})
+
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -1,8 +1,13 @@
 package gox
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
+
+	"github.com/alfredoprograma/gox/ast"
 )
 
 // Top level runtime for Gox language
@@ -16,7 +21,14 @@ func New(args []string) Gox {
 
 // Executes the Gox runtime. If file path is provided as argument, reads source code from it
 // else, executes an interactive REPL prompt.
+//
+// The ast command prints the syntax tree of a file instead of executing it.
 func (g *Gox) Run() {
+	if len(g.args) >= 2 && g.args[1] == "ast" {
+		g.printAST(g.args[2:])
+		return
+	}
+
 	if len(g.args) >= 2 {
 		g.readFromFile(g.args[1])
 		return
@@ -32,9 +44,82 @@ func (g *Gox) readFromFile(path string) {
 		panic(err)
 	}
 
-	fmt.Println(source)
+	program, errs := ast.Parse(string(source))
+
+	if len(errs) > 0 {
+		reportErrors(errs)
+		return
+	}
+
+	reportErrors(program.Warnings)
+
+	if err := program.Execute(ast.NewGlobalEnvironment(os.Stdout)); err != nil {
+		reportErrors([]error{err})
+	}
+}
+
+// Prints the syntax tree of the file given in arguments, in the requested format. Syntax errors are
+// reported, but the tree is printed anyway, with placeholders where source is invalid.
+//
+// gox ast [--json] [--format=text|source|json|dot] <file>
+func (g *Gox) printAST(args []string) {
+	flags := flag.NewFlagSet("ast", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "shorthand for --format=json")
+	format := flags.String("format", "text", "output format: text, source, json or dot")
+	flags.Parse(args)
+
+	if *asJSON {
+		*format = "json"
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: gox ast [--json] [--format=text|source|json|dot] <file>")
+		os.Exit(2)
+	}
+
+	source, err := os.ReadFile(flags.Arg(0))
+
+	if err != nil {
+		panic(err)
+	}
+
+	program, errs := ast.Parse(string(source))
+	reportErrors(errs)
+
+	switch *format {
+	case "text":
+		fmt.Println(program)
+	case "source":
+		fmt.Print(ast.Format(program))
+	case "json":
+		encoded, err := ast.EncodeJSON(program)
+
+		if err != nil {
+			panic(err)
+		}
+
+		var indented bytes.Buffer
+		json.Indent(&indented, encoded, "", "  ")
+		fmt.Println(indented.String())
+	case "dot":
+		fmt.Print(ast.EncodeDOT(program))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown ast format %q\n", *format)
+		os.Exit(2)
+	}
+
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
 }
 
 func (g *Gox) readFromRepl() {
 	panic("implement read source from repl")
 }
+
+// Writes the given errors into the standard error output, one per line.
+func reportErrors(errs []error) {
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
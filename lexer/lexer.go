@@ -13,6 +13,9 @@ type Lexer struct {
 	current uint    // current cursor at source
 	start   uint    // start point of each scan iteration for source
 	line    uint    // current line at source
+
+	lineStart     uint     // cursor where current line starts
+	startPosition Position // position of the start point of each scan iteration
 }
 
 func New(source string) Lexer {
@@ -30,11 +33,14 @@ func New(source string) Lexer {
 func (l *Lexer) Tokenize() ([]Token, []error) {
 	for !l.isEnd() {
 		l.start = l.current
+		l.startPosition = l.position()
 		if err := l.scan(); err != nil {
 			l.registerError(err)
 		}
 	}
 
+	l.start = l.current
+	l.startPosition = l.position()
 	l.addToken(MustCreateTokenFromKind(Eof, l.line))
 	return l.tokens, l.errors
 }
@@ -44,7 +50,7 @@ func (l *Lexer) scan() error {
 
 	switch {
 	case ch == '\n':
-		l.line++
+		l.newLine()
 	case unicode.IsSpace(ch):
 		break
 	case ch == '(':
@@ -55,8 +61,16 @@ func (l *Lexer) scan() error {
 		l.addToken(MustCreateTokenFromKind(LeftBrace, l.line))
 	case ch == '}':
 		l.addToken(MustCreateTokenFromKind(RightBrace, l.line))
+	case ch == '[':
+		l.addToken(MustCreateTokenFromKind(LeftBracket, l.line))
+	case ch == ']':
+		l.addToken(MustCreateTokenFromKind(RightBracket, l.line))
 	case ch == ',':
 		l.addToken(MustCreateTokenFromKind(Comma, l.line))
+	case ch == '.' && l.peek() == '.' && l.peekNext() == '.':
+		l.advance()
+		l.advance()
+		l.addToken(MustCreateTokenFromKind(Ellipsis, l.line))
 	case ch == '.':
 		l.addToken(MustCreateTokenFromKind(Dot, l.line))
 	case ch == '-':
@@ -67,12 +81,26 @@ func (l *Lexer) scan() error {
 		l.addToken(MustCreateTokenFromKind(Semicolon, l.line))
 	case ch == '*':
 		l.addToken(MustCreateTokenFromKind(Star, l.line))
+	case ch == '?' && l.match('.'):
+		l.addToken(MustCreateTokenFromKind(QuestionDot, l.line))
+	case ch == '?' && l.match('?'):
+		l.addToken(MustCreateTokenFromKind(DoubleQuestion, l.line))
+	case ch == '?':
+		l.addToken(MustCreateTokenFromKind(Question, l.line))
+	case ch == ':':
+		l.addToken(MustCreateTokenFromKind(Colon, l.line))
+	case ch == '|':
+		l.addToken(MustCreateTokenFromKind(Pipe, l.line))
+	case ch == '_':
+		l.addToken(MustCreateTokenFromKind(Underscore, l.line))
 	case ch == '!' && l.match('='):
 		l.addToken(MustCreateTokenFromKind(BangEqual, l.line))
 	case ch == '!':
 		l.addToken(MustCreateTokenFromKind(Bang, l.line))
 	case ch == '=' && l.match('='):
 		l.addToken(MustCreateTokenFromKind(DoubleEqual, l.line))
+	case ch == '=' && l.match('>'):
+		l.addToken(MustCreateTokenFromKind(Arrow, l.line))
 	case ch == '=':
 		l.addToken(MustCreateTokenFromKind(Equal, l.line))
 	case ch == '>' && l.match('='):
@@ -102,11 +130,23 @@ func (l *Lexer) scan() error {
 	return nil
 }
 
-// Pushes a new token to the tokens slice.
+// Pushes a new token to the tokens slice, spanning from the start point of current scan iteration to the cursor.
 func (l *Lexer) addToken(token Token) {
+	token.span = Span{l.startPosition, l.position()}
 	l.tokens = append(l.tokens, token)
 }
 
+// Moves to the next line. It assumes the line break is already consumed.
+func (l *Lexer) newLine() {
+	l.line++
+	l.lineStart = l.current
+}
+
+// Returns the position of the current source cursor.
+func (l *Lexer) position() Position {
+	return Position{l.line, l.current - l.lineStart + 1}
+}
+
 // Builds an identifier or keyword token.
 //
 // During the tokenization process, lexer is not capable to determine if some stream
@@ -158,11 +198,11 @@ func (l *Lexer) number() {
 // Also, strings allow multiline by default.
 func (l *Lexer) string() error {
 	for !l.isEnd() && l.peek() != '"' {
-		if l.peek() == '\n' {
-			l.line++
-		}
-
 		l.advance()
+
+		if l.previous() == '\n' {
+			l.newLine()
+		}
 	}
 
 	lexeme := l.source[l.start+1 : l.current]
@@ -193,6 +233,11 @@ func (l *Lexer) advance() rune {
 	return ch
 }
 
+// Takes the character right before current source cursor.
+func (l *Lexer) previous() rune {
+	return rune(l.source[l.current-1])
+}
+
 // Takes the character at current source cursor, but NOT updates to next index.
 func (l *Lexer) peek() rune {
 	if l.isEnd() {
@@ -8,13 +8,15 @@ import (
 
 func TestLexer(t *testing.T) {
 	t.Run("should tokenize single char lexemes", func(t *testing.T) {
-		source := "(){},.-+;/*"
+		source := "(){}[],.-+;/*?:|_"
 		lexer := New(source)
 		expected := []Token{
 			MustCreateTokenFromKind(LeftParen, 1),
 			MustCreateTokenFromKind(RightParen, 1),
 			MustCreateTokenFromKind(LeftBrace, 1),
 			MustCreateTokenFromKind(RightBrace, 1),
+			MustCreateTokenFromKind(LeftBracket, 1),
+			MustCreateTokenFromKind(RightBracket, 1),
 			MustCreateTokenFromKind(Comma, 1),
 			MustCreateTokenFromKind(Dot, 1),
 			MustCreateTokenFromKind(Minus, 1),
@@ -22,28 +24,40 @@ func TestLexer(t *testing.T) {
 			MustCreateTokenFromKind(Semicolon, 1),
 			MustCreateTokenFromKind(Slash, 1),
 			MustCreateTokenFromKind(Star, 1),
+			MustCreateTokenFromKind(Question, 1),
+			MustCreateTokenFromKind(Colon, 1),
+			MustCreateTokenFromKind(Pipe, 1),
+			MustCreateTokenFromKind(Underscore, 1),
 			MustCreateTokenFromKind(Eof, 1),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
 
 	t.Run("should tokenize pairable char lexemes", func(t *testing.T) {
-		source := "!!====>>=<<="
+		source := "!!==== =>>>=<<=.. ... ?.??"
 		lexer := New(source)
 		expected := []Token{
 			MustCreateTokenFromKind(Bang, 1),
 			MustCreateTokenFromKind(BangEqual, 1),
 			MustCreateTokenFromKind(DoubleEqual, 1),
 			MustCreateTokenFromKind(Equal, 1),
+			MustCreateTokenFromKind(Arrow, 1),
 			MustCreateTokenFromKind(Greater, 1),
 			MustCreateTokenFromKind(GreaterEqual, 1),
 			MustCreateTokenFromKind(Less, 1),
 			MustCreateTokenFromKind(LessEqual, 1),
+			MustCreateTokenFromKind(Dot, 1),
+			MustCreateTokenFromKind(Dot, 1),
+			MustCreateTokenFromKind(Ellipsis, 1),
+			MustCreateTokenFromKind(QuestionDot, 1),
+			MustCreateTokenFromKind(DoubleQuestion, 1),
 			MustCreateTokenFromKind(Eof, 1),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
@@ -57,6 +71,7 @@ func TestLexer(t *testing.T) {
 			MustCreateTokenFromKind(Eof, 1),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
@@ -69,6 +84,7 @@ func TestLexer(t *testing.T) {
 			MustCreateTokenFromKind(Eof, 2),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
@@ -84,6 +100,7 @@ func TestLexer(t *testing.T) {
 			MustCreateTokenFromKind(Eof, 1),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
@@ -99,21 +116,25 @@ func TestLexer(t *testing.T) {
 			MustCreateTokenFromKind(Eof, 1),
 		}
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
 
 		assert.Equal(t, expected, got)
 	})
 
 	t.Run("should tokenize keywords", func(t *testing.T) {
-		source := "and class else false function for if null or print return super this true var while"
+		source := "and break class continue else false function for if match null or print return super this true var while"
 		lexer := New(source)
 		expected := []Token{
 			MustCreateTokenFromKind(And, 1),
+			MustCreateTokenFromKind(Break, 1),
 			MustCreateTokenFromKind(Class, 1),
+			MustCreateTokenFromKind(Continue, 1),
 			MustCreateTokenFromKind(Else, 1),
 			MustCreateTokenFromKind(False, 1),
 			MustCreateTokenFromKind(Function, 1),
 			MustCreateTokenFromKind(For, 1),
 			MustCreateTokenFromKind(If, 1),
+			MustCreateTokenFromKind(Match, 1),
 			MustCreateTokenFromKind(Null, 1),
 			MustCreateTokenFromKind(Or, 1),
 			MustCreateTokenFromKind(Print, 1),
@@ -127,6 +148,30 @@ func TestLexer(t *testing.T) {
 		}
 
 		got, _ := lexer.Tokenize()
+		got = withoutSpans(got)
+
+		assert.Equal(t, expected, got)
+	})
+
+	t.Run("should record the span of each token", func(t *testing.T) {
+		source := "var x =\n  \"a\nb\" >= 10;"
+		lexer := New(source)
+		expected := []Span{
+			{Position{1, 1}, Position{1, 4}},
+			{Position{1, 5}, Position{1, 6}},
+			{Position{1, 7}, Position{1, 8}},
+			{Position{2, 3}, Position{3, 3}},
+			{Position{3, 4}, Position{3, 6}},
+			{Position{3, 7}, Position{3, 9}},
+			{Position{3, 9}, Position{3, 10}},
+			{Position{3, 10}, Position{3, 10}},
+		}
+		tokens, _ := lexer.Tokenize()
+		got := make([]Span, len(tokens))
+
+		for i, token := range tokens {
+			got[i] = token.Span()
+		}
 
 		assert.Equal(t, expected, got)
 	})
@@ -154,3 +199,12 @@ func TestLexer(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+// Discards token spans, so expected tokens can be built without them.
+func withoutSpans(tokens []Token) []Token {
+	for i := range tokens {
+		tokens[i].span = Span{}
+	}
+
+	return tokens
+}
@@ -10,6 +10,8 @@ const (
 	RightParen
 	LeftBrace
 	RightBrace
+	LeftBracket
+	RightBracket
 	Comma
 	Dot
 	Minus
@@ -17,12 +19,20 @@ const (
 	Semicolon
 	Slash
 	Star
+	Question
+	Colon
+	Pipe
+	Underscore
 
 	// Pairable character tokens
 	Bang
 	BangEqual
 	Equal
 	DoubleEqual
+	Arrow
+	Ellipsis
+	QuestionDot
+	DoubleQuestion
 	Greater
 	GreaterEqual
 	Less
@@ -35,12 +45,15 @@ const (
 
 	// Keywords
 	And
+	Break
 	Class
+	Continue
 	Else
 	False
 	Function
 	For
 	If
+	Match
 	Null
 	Or
 	Print
@@ -55,42 +68,55 @@ const (
 )
 
 var TokenKindToLexemeMap = map[TokenKind]string{
-	LeftParen:    "(",
-	RightParen:   ")",
-	LeftBrace:    "{",
-	RightBrace:   "}",
-	Comma:        ",",
-	Dot:          ".",
-	Minus:        "-",
-	Plus:         "+",
-	Semicolon:    ";",
-	Slash:        "/",
-	Star:         "*",
-	Bang:         "!",
-	BangEqual:    "!=",
-	Equal:        "=",
-	DoubleEqual:  "==",
-	Greater:      ">",
-	GreaterEqual: ">=",
-	Less:         "<",
-	LessEqual:    "<=",
-	And:          "and",
-	Class:        "class",
-	Else:         "else",
-	False:        "false",
-	Function:     "function",
-	For:          "for",
-	If:           "if",
-	Null:         "null",
-	Or:           "or",
-	Print:        "print",
-	Return:       "return",
-	Super:        "super",
-	This:         "this",
-	True:         "true",
-	Var:          "var",
-	While:        "while",
-	Eof:          "",
+	LeftParen:      "(",
+	RightParen:     ")",
+	LeftBrace:      "{",
+	RightBrace:     "}",
+	LeftBracket:    "[",
+	RightBracket:   "]",
+	Comma:          ",",
+	Dot:            ".",
+	Minus:          "-",
+	Plus:           "+",
+	Semicolon:      ";",
+	Slash:          "/",
+	Star:           "*",
+	Question:       "?",
+	Colon:          ":",
+	Pipe:           "|",
+	Underscore:     "_",
+	Bang:           "!",
+	BangEqual:      "!=",
+	Equal:          "=",
+	DoubleEqual:    "==",
+	Arrow:          "=>",
+	Ellipsis:       "...",
+	QuestionDot:    "?.",
+	DoubleQuestion: "??",
+	Greater:        ">",
+	GreaterEqual:   ">=",
+	Less:           "<",
+	LessEqual:      "<=",
+	And:            "and",
+	Break:          "break",
+	Class:          "class",
+	Continue:       "continue",
+	Else:           "else",
+	False:          "false",
+	Function:       "function",
+	For:            "for",
+	If:             "if",
+	Match:          "match",
+	Null:           "null",
+	Or:             "or",
+	Print:          "print",
+	Return:         "return",
+	Super:          "super",
+	This:           "this",
+	True:           "true",
+	Var:            "var",
+	While:          "while",
+	Eof:            "",
 }
 
 var LexemeToTokenKindMap = func() map[string]TokenKind {
@@ -103,15 +129,47 @@ var LexemeToTokenKindMap = func() map[string]TokenKind {
 	return transformer
 }()
 
+// Location of a character within source. Both line and column start at 1.
+type Position struct {
+	Line   uint `json:"line"`
+	Column uint `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Range of source between two positions. End points right after the last character.
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Builds the span which covers from the start of the first span to the end of the last one.
+func Join(first Span, last Span) Span {
+	return Span{first.Start, last.End}
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("%s-%s", s.Start, s.End)
+}
+
 type Token struct {
 	Kind   TokenKind
 	Lexeme string
 	line   uint
+	span   Span // zero when token wasn't read from source
 }
 
 // Creates a new token from given args.
 func CreateToken(kind TokenKind, lexeme string, line uint) Token {
-	return Token{kind, lexeme, line}
+	return Token{kind, lexeme, line, Span{}}
+}
+
+// Creates a new token found at the given span of source. Token line is the line where it ends,
+// like tokens read by the lexer.
+func CreateTokenAt(kind TokenKind, lexeme string, span Span) Token {
+	return Token{kind, lexeme, span.End.Line, span}
 }
 
 // Creates a token with its corresponding fixed lexeme based on the provided TokenKind.
@@ -124,7 +182,17 @@ func MustCreateTokenFromKind(kind TokenKind, line uint) Token {
 		panic("unexpected use of NewTokenFromMap. Provided TokenKind doesn't match with any lexeme")
 	}
 
-	return Token{kind, lexeme, line}
+	return Token{kind, lexeme, line, Span{}}
+}
+
+// Returns the source line where the token was found.
+func (t Token) Line() uint {
+	return t.line
+}
+
+// Returns the range of source where the token was found.
+func (t Token) Span() Span {
+	return t.span
 }
 
 func (t Token) String() string {
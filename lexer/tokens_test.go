@@ -9,7 +9,7 @@ import (
 func TestTokens(t *testing.T) {
 	t.Run("should create new token from token kind", func(t *testing.T) {
 		for kind, lexeme := range TokenKindToLexemeMap {
-			expected := Token{kind, lexeme, 1}
+			expected := Token{Kind: kind, Lexeme: lexeme, line: 1}
 			got := MustCreateTokenFromKind(kind, 1)
 
 			assert.Equal(t, expected, got)